@@ -0,0 +1,188 @@
+package Functions
+
+//AffineBacktrack holds backtracking pointers for each of the three layers of an
+//affine gap alignment. Pointers in the Match layer are "DIAG", "DELETE", "INSERT", or
+//"FREE", where "DELETE" and "INSERT" jump to the same node of the other layer.
+//Pointers in the Insert and Delete layers are "EXTEND" (stay in the gap layer) or
+//"OPEN" (return to the Match layer).
+type AffineBacktrack struct {
+	Match  [][]string
+	Insert [][]string
+	Delete [][]string
+}
+
+//GlobalAffineAlignment takes two strings, along with match and mismatch scores and gap
+//opening and extension penalties. It returns a maximum score global alignment of the strings
+//in which a gap of length L is penalized by gapOpen + (L-1)*gapExtend.
+func GlobalAffineAlignment(str0, str1 string, match, mismatch, gapOpen, gapExtend float64) Alignment {
+	return globalAffineAlignment(str0, str1, matchMismatch{match, mismatch}, gapOpen, gapExtend)
+}
+
+//GlobalAffineAlignmentWithMatrix is the substitution matrix analogue of GlobalAffineAlignment.
+func GlobalAffineAlignmentWithMatrix(str0, str1 string, scoring ScoringMatrix, gapOpen, gapExtend float64) Alignment {
+	return globalAffineAlignment(str0, str1, scoring, gapOpen, gapExtend)
+}
+
+//LocalAffineAlignment is the affine gap analogue of LocalAlignment. It returns a maximum
+//score local alignment along with start0, end0, start1, and end1 such that the alignment
+//is of [start0: end0] and [start1: end1] in the respective strings.
+func LocalAffineAlignment(str0, str1 string, match, mismatch, gapOpen, gapExtend float64) (Alignment, int, int, int, int) {
+	return localAffineAlignment(str0, str1, matchMismatch{match, mismatch}, gapOpen, gapExtend)
+}
+
+//LocalAffineAlignmentWithMatrix is the substitution matrix analogue of LocalAffineAlignment.
+func LocalAffineAlignmentWithMatrix(str0, str1 string, scoring ScoringMatrix, gapOpen, gapExtend float64) (Alignment, int, int, int, int) {
+	return localAffineAlignment(str0, str1, scoring, gapOpen, gapExtend)
+}
+
+func globalAffineAlignment(str0, str1 string, scorer symbolScorer, gapOpen, gapExtend float64) Alignment {
+	tables := affineScoreTables(str0, str1, scorer, gapOpen, gapExtend, false)
+	backtrack := affineBacktrackTables(str0, str1, tables, scorer, gapOpen, gapExtend, false)
+	optAlignment, _, _ := OutputAffineAlignment(str0, str1, backtrack)
+	return optAlignment
+}
+
+func localAffineAlignment(str0, str1 string, scorer symbolScorer, gapOpen, gapExtend float64) (Alignment, int, int, int, int) {
+	tables := affineScoreTables(str0, str1, scorer, gapOpen, gapExtend, true)
+	end0, end1 := MaxTableCell(tables.Match)
+	backtrack := affineBacktrackTables(str0, str1, tables, scorer, gapOpen, gapExtend, true)
+	optAlignment, start0, start1 := OutputAffineAlignment(str0[:end0], str1[:end1], backtrack)
+	return optAlignment, start0, end0, start1, end1
+}
+
+//OutputAffineAlignment takes two strings that end at the sink of an alignment, along with
+//affine backtracking pointers. It walks back through the three layers starting at the Match
+//layer and returns the alignment along with the positions in each string where it starts.
+func OutputAffineAlignment(str0, str1 string, backtrack AffineBacktrack) (Alignment, int, int) {
+	var a Alignment
+	layer := "MATCH"
+
+	for {
+		row := len(str0)
+		col := len(str1)
+
+		if layer == "MATCH" {
+			pointer := backtrack.Match[row][col]
+			if pointer == "FREE" {
+				return a, row, col
+			} else if pointer == "DIAG" {
+				a[0] = string(str0[row-1]) + a[0]
+				a[1] = string(str1[col-1]) + a[1]
+				str0 = str0[:len(str0)-1]
+				str1 = str1[:len(str1)-1]
+			} else if pointer == "DELETE" || pointer == "INSERT" {
+				//switch layers without consuming a symbol
+				layer = pointer
+			} else {
+				panic("Illegal backtracking pointer.")
+			}
+		} else if layer == "DELETE" {
+			pointer := backtrack.Delete[row][col]
+			a[0] = string(str0[row-1]) + a[0]
+			a[1] = "-" + a[1]
+			str0 = str0[:len(str0)-1]
+			if pointer == "OPEN" {
+				layer = "MATCH"
+			} else if pointer != "EXTEND" {
+				panic("Illegal backtracking pointer.")
+			}
+		} else {
+			pointer := backtrack.Insert[row][col]
+			a[0] = "-" + a[0]
+			a[1] = string(str1[col-1]) + a[1]
+			str1 = str1[:len(str1)-1]
+			if pointer == "OPEN" {
+				layer = "MATCH"
+			} else if pointer != "EXTEND" {
+				panic("Illegal backtracking pointer.")
+			}
+		}
+	}
+}
+
+//AffineBacktrackTables takes two strings, their affine score tables, the penalties used to
+//produce them, and whether the tables are for local alignment. It returns backtracking
+//pointers for all three layers.
+func AffineBacktrackTables(str0, str1 string, tables AffineScoreTables, match, mismatch, gapOpen, gapExtend float64, local bool) AffineBacktrack {
+	return affineBacktrackTables(str0, str1, tables, matchMismatch{match, mismatch}, gapOpen, gapExtend, local)
+}
+
+func affineBacktrackTables(str0, str1 string, tables AffineScoreTables, scorer symbolScorer, gapOpen, gapExtend float64, local bool) AffineBacktrack {
+	numRows := len(str0) + 1
+	numCols := len(str1) + 1
+
+	var backtrack AffineBacktrack
+	backtrack.Match = InitializeStringTable(numRows, numCols)
+	backtrack.Insert = InitializeStringTable(numRows, numCols)
+	backtrack.Delete = InitializeStringTable(numRows, numCols)
+
+	backtrack.Match[0][0] = "FREE"
+
+	//0-th row and column
+	for j := 1; j < numCols; j++ {
+		if local {
+			backtrack.Match[0][j] = "FREE"
+		} else {
+			backtrack.Match[0][j] = "INSERT"
+			backtrack.Insert[0][j] = "EXTEND"
+		}
+	}
+	for i := 1; i < numRows; i++ {
+		if local {
+			backtrack.Match[i][0] = "FREE"
+		} else {
+			backtrack.Match[i][0] = "DELETE"
+			backtrack.Delete[i][0] = "EXTEND"
+		}
+	}
+	if !local {
+		if numCols > 1 {
+			backtrack.Insert[0][1] = "OPEN"
+		}
+		if numRows > 1 {
+			backtrack.Delete[1][0] = "OPEN"
+		}
+	}
+
+	for i := 1; i < numRows; i++ {
+		for j := 1; j < numCols; j++ {
+			//gap layers: did we extend a gap or open one?
+			if tables.Delete[i][j] == tables.Delete[i-1][j]-gapExtend {
+				backtrack.Delete[i][j] = "EXTEND"
+			} else {
+				backtrack.Delete[i][j] = "OPEN"
+			}
+			if tables.Insert[i][j] == tables.Insert[i][j-1]-gapExtend {
+				backtrack.Insert[i][j] = "EXTEND"
+			} else {
+				backtrack.Insert[i][j] = "OPEN"
+			}
+
+			diagonalWeight := scorer.Score(str0[i-1], str1[j-1])
+
+			if local && tables.Match[i][j] == 0.0 {
+				backtrack.Match[i][j] = "FREE"
+			} else if tables.Match[i][j] == tables.Match[i-1][j-1]+diagonalWeight {
+				backtrack.Match[i][j] = "DIAG"
+			} else if tables.Match[i][j] == tables.Delete[i][j] {
+				backtrack.Match[i][j] = "DELETE"
+			} else if tables.Match[i][j] == tables.Insert[i][j] {
+				backtrack.Match[i][j] = "INSERT"
+			} else {
+				panic("Error: score table value not reached by any edge.")
+			}
+		}
+	}
+
+	return backtrack
+}
+
+//InitializeStringTable takes a number of rows and columns and returns a 2-D slice
+//of strings with these dimensions, all set to the empty string.
+func InitializeStringTable(numRows, numCols int) [][]string {
+	table := make([][]string, numRows)
+	for i := range table {
+		table[i] = make([]string, numCols)
+	}
+	return table
+}
@@ -0,0 +1,94 @@
+package Functions
+
+import "math"
+
+//AffineScoreTables holds the three layers of dynamic programming scores used for
+//alignment with affine gap penalties. Match holds the best score of an alignment whose
+//last column may be anything, Delete the best score of an alignment ending with a symbol
+//of str0 against a gap, and Insert the best score of an alignment ending with a symbol
+//of str1 against a gap.
+type AffineScoreTables struct {
+	Match  [][]float64
+	Insert [][]float64
+	Delete [][]float64
+}
+
+//GlobalAffineScoreTables takes two strings along with match, mismatch, gap opening, and
+//gap extension penalties. It returns the three layers of dynamic programming scores for
+//global alignment, where a gap of length L costs gapOpen + (L-1)*gapExtend.
+func GlobalAffineScoreTables(str0, str1 string, match, mismatch, gapOpen, gapExtend float64) AffineScoreTables {
+	return affineScoreTables(str0, str1, matchMismatch{match, mismatch}, gapOpen, gapExtend, false)
+}
+
+//LocalAffineScoreTables is the local alignment analogue of GlobalAffineScoreTables;
+//every node of the Match layer can be reached by a free ride from the source.
+func LocalAffineScoreTables(str0, str1 string, match, mismatch, gapOpen, gapExtend float64) AffineScoreTables {
+	return affineScoreTables(str0, str1, matchMismatch{match, mismatch}, gapOpen, gapExtend, true)
+}
+
+//affineScoreTables fills the Gotoh recurrences shared by global and local affine alignment.
+func affineScoreTables(str0, str1 string, scorer symbolScorer, gapOpen, gapExtend float64, local bool) AffineScoreTables {
+	if len(str0) == 0 || len(str1) == 0 {
+		panic("Zero length strings.")
+	}
+
+	numRows := len(str0) + 1
+	numCols := len(str1) + 1
+
+	var tables AffineScoreTables
+	tables.Match = InitializeFloatTable(numRows, numCols)
+	tables.Insert = InitializeFloatTable(numRows, numCols)
+	tables.Delete = InitializeFloatTable(numRows, numCols)
+
+	negInf := math.Inf(-1)
+
+	//a gap layer can't be occupied at the source, and the 0-th row (column) can't
+	//end with a deletion (insertion)
+	tables.Insert[0][0] = negInf
+	tables.Delete[0][0] = negInf
+	for j := 1; j < numCols; j++ {
+		tables.Delete[0][j] = negInf
+		if local {
+			tables.Insert[0][j] = negInf
+		} else {
+			tables.Insert[0][j] = -gapOpen - float64(j-1)*gapExtend
+			tables.Match[0][j] = tables.Insert[0][j]
+		}
+	}
+	for i := 1; i < numRows; i++ {
+		tables.Insert[i][0] = negInf
+		if local {
+			tables.Delete[i][0] = negInf
+		} else {
+			tables.Delete[i][0] = -gapOpen - float64(i-1)*gapExtend
+			tables.Match[i][0] = tables.Delete[i][0]
+		}
+	}
+
+	for i := 1; i < numRows; i++ {
+		for j := 1; j < numCols; j++ {
+			//extend an existing gap or open a new one from the match layer
+			tables.Delete[i][j] = MaxFloat(tables.Delete[i-1][j]-gapExtend, tables.Match[i-1][j]-gapOpen)
+			tables.Insert[i][j] = MaxFloat(tables.Insert[i][j-1]-gapExtend, tables.Match[i][j-1]-gapOpen)
+
+			diagValue := tables.Match[i-1][j-1] + scorer.Score(str0[i-1], str1[j-1])
+
+			tables.Match[i][j] = MaxFloat(diagValue, tables.Delete[i][j], tables.Insert[i][j])
+			if local {
+				tables.Match[i][j] = MaxFloat(0.0, tables.Match[i][j])
+			}
+		}
+	}
+
+	return tables
+}
+
+//InitializeFloatTable takes a number of rows and columns and returns a 2-D slice
+//of float64 values with these dimensions, all set to zero.
+func InitializeFloatTable(numRows, numCols int) [][]float64 {
+	table := make([][]float64, numRows)
+	for i := range table {
+		table[i] = make([]float64, numCols)
+	}
+	return table
+}
@@ -0,0 +1,192 @@
+package Functions
+
+//AlignmentMode determines which end gaps of an alignment are free, i.e., which prefixes and
+//suffixes of the two strings may be skipped without penalty.
+type AlignmentMode int
+
+const (
+	//GlobalMode penalizes every end gap, just like GlobalAlignment.
+	GlobalMode AlignmentMode = iota
+	//SemiGlobalMode makes all end gaps free, in both strings.
+	SemiGlobalMode
+	//FittingMode fits all of str1 (such as a primer or amplicon) somewhere inside str0
+	//(such as a genome), so skipping a prefix or suffix of str0 is free.
+	FittingMode
+	//OverlapMode aligns a suffix of str0 against a prefix of str1, as when joining
+	//overlapping reads, so skipping a prefix of str0 or a suffix of str1 is free.
+	OverlapMode
+)
+
+//String returns the name of an alignment mode.
+func (mode AlignmentMode) String() string {
+	switch mode {
+	case GlobalMode:
+		return "global"
+	case SemiGlobalMode:
+		return "semi-global"
+	case FittingMode:
+		return "fitting"
+	case OverlapMode:
+		return "overlap"
+	}
+	return "unknown"
+}
+
+//EndGaps records which end gaps of an alignment are free.
+type EndGaps struct {
+	Str0Prefix bool
+	Str0Suffix bool
+	Str1Prefix bool
+	Str1Suffix bool
+}
+
+//FreeEndGaps returns the end gaps that are free under an alignment mode.
+func (mode AlignmentMode) FreeEndGaps() EndGaps {
+	switch mode {
+	case GlobalMode:
+		return EndGaps{}
+	case SemiGlobalMode:
+		return EndGaps{true, true, true, true}
+	case FittingMode:
+		return EndGaps{Str0Prefix: true, Str0Suffix: true}
+	case OverlapMode:
+		return EndGaps{Str0Prefix: true, Str1Suffix: true}
+	}
+	panic("Error: unknown alignment mode.")
+}
+
+//ModeAlignment takes two strings, match, mismatch, and gap scores, and an alignment mode.
+//It returns a maximum score alignment in which the end gaps chosen by the mode are free,
+//along with start0, end0, start1, and end1 such that the alignment is of [start0: end0] and
+//[start1: end1] in the respective strings. The skipped prefixes and suffixes are not included
+//in the alignment.
+func ModeAlignment(str0, str1 string, match, mismatch, gap float64, mode AlignmentMode) (Alignment, int, int, int, int) {
+	return modeAlignment(str0, str1, matchMismatch{match, mismatch}, gap, mode)
+}
+
+//ModeAlignmentWithMatrix is the substitution matrix analogue of ModeAlignment.
+func ModeAlignmentWithMatrix(str0, str1 string, scoring ScoringMatrix, gap float64, mode AlignmentMode) (Alignment, int, int, int, int) {
+	return modeAlignment(str0, str1, scoring, gap, mode)
+}
+
+func modeAlignment(str0, str1 string, scorer symbolScorer, gap float64, mode AlignmentMode) (Alignment, int, int, int, int) {
+	scoreTable := modeScoreTable(str0, str1, scorer, gap, mode)
+	end0, end1 := ModeSink(scoreTable, mode)
+	backtrack := modeBacktrack(str0, str1, scoreTable, scorer, gap, mode)
+	optAlignment, start0, start1 := OutputLocalAlignment(str0[:end0], str1[:end1], backtrack)
+	return optAlignment, start0, end0, start1, end1
+}
+
+//ModeScoreTable takes two strings, alignment penalties, and an alignment mode. It returns
+//a 2-D array holding dynamic programming scores in which free prefixes cost nothing.
+func ModeScoreTable(str0, str1 string, match, mismatch, gap float64, mode AlignmentMode) [][]float64 {
+	return modeScoreTable(str0, str1, matchMismatch{match, mismatch}, gap, mode)
+}
+
+func modeScoreTable(str0, str1 string, scorer symbolScorer, gap float64, mode AlignmentMode) [][]float64 {
+	free := mode.FreeEndGaps()
+
+	if len(str0) == 0 || len(str1) == 0 {
+		panic("Zero length strings.")
+	}
+
+	numRows := len(str0) + 1
+	numCols := len(str1) + 1
+	scoreTable := InitializeFloatTable(numRows, numCols)
+
+	//penalize the 0-th row and column as all gaps, unless the prefix is free
+	for j := 1; j < numCols; j++ {
+		if !free.Str1Prefix {
+			scoreTable[0][j] = float64(j) * (-gap)
+		}
+	}
+	for i := 1; i < numRows; i++ {
+		if !free.Str0Prefix {
+			scoreTable[i][0] = float64(i) * (-gap)
+		}
+	}
+
+	for i := 1; i < numRows; i++ {
+		for j := 1; j < numCols; j++ {
+			upValue := scoreTable[i-1][j] - gap
+			leftValue := scoreTable[i][j-1] - gap
+			diagValue := scoreTable[i-1][j-1] + scorer.Score(str0[i-1], str1[j-1])
+			scoreTable[i][j] = MaxFloat(upValue, leftValue, diagValue)
+		}
+	}
+
+	return scoreTable
+}
+
+//ModeSink takes a score table produced by ModeScoreTable and the mode used to produce it.
+//It returns the node where an optimal alignment ends: the bottom right corner, or the best
+//node of the last column (row) if a suffix of str0 (str1) may be skipped for free.
+func ModeSink(scoreTable [][]float64, mode AlignmentMode) (int, int) {
+	free := mode.FreeEndGaps()
+	lastRow := len(scoreTable) - 1
+	lastCol := len(scoreTable[0]) - 1
+
+	end0, end1 := lastRow, lastCol
+	if free.Str0Suffix {
+		for i := 0; i <= lastRow; i++ {
+			if scoreTable[i][lastCol] > scoreTable[end0][end1] {
+				end0, end1 = i, lastCol
+			}
+		}
+	}
+	if free.Str1Suffix {
+		for j := 0; j <= lastCol; j++ {
+			if scoreTable[lastRow][j] > scoreTable[end0][end1] {
+				end0, end1 = lastRow, j
+			}
+		}
+	}
+	return end0, end1
+}
+
+//ModeBacktrack takes two strings, their score table from ModeScoreTable, the penalties, and the
+//alignment mode. It returns backtracking pointers ("UP", "LEFT", "DIAG", "FREE"), where "FREE"
+//marks the node at which the alignment starts.
+func ModeBacktrack(str0, str1 string, scoreTable [][]float64, match, mismatch, gap float64, mode AlignmentMode) [][]string {
+	return modeBacktrack(str0, str1, scoreTable, matchMismatch{match, mismatch}, gap, mode)
+}
+
+func modeBacktrack(str0, str1 string, scoreTable [][]float64, scorer symbolScorer, gap float64, mode AlignmentMode) [][]string {
+	free := mode.FreeEndGaps()
+	numRows := len(str0) + 1
+	numCols := len(str1) + 1
+
+	backtrack := InitializeStringTable(numRows, numCols)
+
+	backtrack[0][0] = "FREE"
+	for j := 1; j < numCols; j++ {
+		if free.Str1Prefix {
+			backtrack[0][j] = "FREE"
+		} else {
+			backtrack[0][j] = "LEFT"
+		}
+	}
+	for i := 1; i < numRows; i++ {
+		if free.Str0Prefix {
+			backtrack[i][0] = "FREE"
+		} else {
+			backtrack[i][0] = "UP"
+		}
+	}
+
+	for i := 1; i < numRows; i++ {
+		for j := 1; j < numCols; j++ {
+			if scoreTable[i][j] == scoreTable[i-1][j-1]+scorer.Score(str0[i-1], str1[j-1]) {
+				backtrack[i][j] = "DIAG"
+			} else if scoreTable[i][j] == scoreTable[i-1][j]-gap {
+				backtrack[i][j] = "UP"
+			} else if scoreTable[i][j] == scoreTable[i][j-1]-gap {
+				backtrack[i][j] = "LEFT"
+			} else {
+				panic("Error: score table value not reached by any edge.")
+			}
+		}
+	}
+
+	return backtrack
+}
@@ -0,0 +1,220 @@
+package Functions
+
+import (
+	"strconv"
+	"strings"
+)
+
+//VariantEffect describes how a variant changes the protein encoded by a CDS.
+type VariantEffect string
+
+const (
+	NonCoding     VariantEffect = "non-coding"
+	Synonymous    VariantEffect = "synonymous"
+	Nonsynonymous VariantEffect = "non-synonymous"
+	Frameshift    VariantEffect = "frameshift"
+	InFrameIndel  VariantEffect = "in-frame indel"
+	//IndelInCodon marks a substitution in a codon that an indel also changes, so the codon
+	//can't be translated on its own and no amino acid change is given.
+	IndelInCodon VariantEffect = "substitution in codon with indel"
+)
+
+//AnnotatedVariant is a variant along with its location in the annotated genome. Variants that
+//fall in a CDS carry the 1-based number of the affected codon, the variant's 1-based position
+//within that codon, and (for substitutions) the codon and amino acid change, such as "D614G".
+type AnnotatedVariant struct {
+	Variant
+	Gene            string
+	CDS             string
+	CodonNumber     int
+	CodonPosition   int
+	RefCodon        string
+	AltCodon        string
+	AminoAcidChange string
+	Effect          VariantEffect
+}
+
+//AnnotateVariants takes an alignment, the row (0 or 1) holding the annotated genome, and the
+//genome's features. It calls the variants of the other row against the annotated row and
+//returns one annotation for every CDS each variant falls in, or a single non-coding annotation
+//(naming the enclosing gene, if any) for variants outside every CDS.
+func AnnotateVariants(a Alignment, annotatedRow int, features []Feature) []AnnotatedVariant {
+	if annotatedRow == 1 {
+		a = Alignment{a[1], a[0]}
+	} else if annotatedRow != 0 {
+		panic("Error: annotated row must be 0 or 1.")
+	}
+
+	reference := strings.Replace(a[0], "-", "", -1)
+
+	//refColumn[p] is the alignment column holding the reference symbol at 0-based position p
+	refColumn := make([]int, 0, len(reference))
+	for col := 0; col < len(a[0]); col++ {
+		if a[0][col] != '-' {
+			refColumn = append(refColumn, col)
+		}
+	}
+
+	annotated := make([]AnnotatedVariant, 0)
+	for _, v := range CallVariants(a) {
+		first, last := v.Pos, v.Pos+len(v.Ref)-1
+		if v.Type != SNP && v.Pos > 1 && v.Ref[0] == v.Alt[0] {
+			//skip the anchor symbol, which is unchanged
+			first++
+			if v.Type == Insertion {
+				//an insertion lies between the anchor and the next symbol
+				first, last = v.Pos, v.Pos
+			}
+		}
+
+		found := false
+		for _, f := range features {
+			if f.Type != "CDS" || !overlaps(f, first, last) {
+				continue
+			}
+			found = true
+			annotated = append(annotated, annotateInCDS(a, reference, refColumn, v, f, first))
+		}
+
+		if !found {
+			av := AnnotatedVariant{Variant: v, Effect: NonCoding}
+			for _, f := range features {
+				if f.Type == "gene" && overlaps(f, first, last) {
+					av.Gene = f.Name
+					break
+				}
+			}
+			annotated = append(annotated, av)
+		}
+	}
+
+	return annotated
+}
+
+//overlaps returns true if any position from first to last (1-based, inclusive) lies in a feature.
+func overlaps(f Feature, first, last int) bool {
+	for pos := first; pos <= last; pos++ {
+		if f.Contains(pos) {
+			return true
+		}
+	}
+	return false
+}
+
+//annotateInCDS places a variant within a CDS, using pos as the first affected reference position.
+func annotateInCDS(a Alignment, reference string, refColumn []int, v Variant, cds Feature, pos int) AnnotatedVariant {
+	av := AnnotatedVariant{Variant: v, Gene: cds.Gene, CDS: cds.Name}
+	if av.Gene == "" {
+		av.Gene = cds.Name
+	}
+
+	//find the first position of the variant that is within the CDS
+	for !cds.Contains(pos) {
+		pos++
+	}
+	offset := cdsOffset(cds, pos)
+	av.CodonNumber = offset/3 + 1
+	av.CodonPosition = offset%3 + 1
+
+	if v.Type != SNP {
+		if (len(v.Alt)-len(v.Ref))%3 != 0 {
+			av.Effect = Frameshift
+		} else {
+			av.Effect = InFrameIndel
+		}
+		return av
+	}
+
+	//read the codon from both rows of the alignment, in the CDS's direction
+	codonStart := offset - offset%3
+	var refCodon, altCodon []byte
+	indel := false
+	previous := 0
+	for i := 0; i < 3; i++ {
+		p := cdsPosition(cds, codonStart+i)
+		if p < 1 || p > len(reference) {
+			return av
+		}
+		//symbols inserted in the query between neighbouring positions of the codon
+		//lie in the columns between them
+		if i > 0 && (p-previous == 1 || previous-p == 1) {
+			gapColumns := refColumn[p-1] - refColumn[previous-1]
+			if gapColumns != 1 && gapColumns != -1 {
+				indel = true
+			}
+		}
+		previous = p
+		r := reference[p-1]
+		q := a[1][refColumn[p-1]]
+		if q == '-' {
+			indel = true
+		}
+		if cds.Strand == '-' {
+			r = complementBase(r)
+			q = complementBase(q)
+		}
+		refCodon = append(refCodon, r)
+		altCodon = append(altCodon, q)
+	}
+	av.RefCodon = string(refCodon)
+	av.AltCodon = string(altCodon)
+	if indel {
+		av.Effect = IndelInCodon
+		return av
+	}
+
+	refAminoAcid := TranslateCodon(av.RefCodon)
+	altAminoAcid := TranslateCodon(av.AltCodon)
+	av.AminoAcidChange = string(refAminoAcid) + strconv.Itoa(av.CodonNumber) + string(altAminoAcid)
+	if refAminoAcid == altAminoAcid {
+		av.Effect = Synonymous
+	} else {
+		av.Effect = Nonsynonymous
+	}
+	return av
+}
+
+//cdsOffset returns the 0-based offset of a genome position within a CDS, counted in the
+//direction of translation.
+func cdsOffset(cds Feature, pos int) int {
+	offset := 0
+	if cds.Strand == '-' {
+		for i := len(cds.Segments) - 1; i >= 0; i-- {
+			s := cds.Segments[i]
+			if pos >= s.Start && pos <= s.End {
+				return offset + s.End - pos
+			}
+			offset += s.End - s.Start + 1
+		}
+	} else {
+		for _, s := range cds.Segments {
+			if pos >= s.Start && pos <= s.End {
+				return offset + pos - s.Start
+			}
+			offset += s.End - s.Start + 1
+		}
+	}
+	panic("Error: position is not in the CDS.")
+}
+
+//cdsPosition is the inverse of cdsOffset: it returns the genome position at a 0-based
+//offset along a CDS, or -1 if the offset is past the end of the CDS.
+func cdsPosition(cds Feature, offset int) int {
+	if cds.Strand == '-' {
+		for i := len(cds.Segments) - 1; i >= 0; i-- {
+			s := cds.Segments[i]
+			if offset < s.End-s.Start+1 {
+				return s.End - offset
+			}
+			offset -= s.End - s.Start + 1
+		}
+	} else {
+		for _, s := range cds.Segments {
+			if offset < s.End-s.Start+1 {
+				return s.Start + offset
+			}
+			offset -= s.End - s.Start + 1
+		}
+	}
+	return -1
+}
@@ -0,0 +1,334 @@
+package Functions
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+//FeatureSegment is one contiguous piece of a feature, with 1-based inclusive coordinates.
+type FeatureSegment struct {
+	Start int
+	End   int
+}
+
+//Feature is an annotated region of a genome, such as a gene or a coding sequence (CDS), read
+//from a GFF3 or GenBank file. A feature made of several pieces, such as a CDS with a ribosomal
+//frameshift, has one segment per piece, listed in order along the genome.
+type Feature struct {
+	SeqID    string
+	Type     string
+	ID       string
+	Name     string
+	Gene     string
+	Product  string
+	Strand   byte // '+', '-', or '.'
+	Segments []FeatureSegment
+}
+
+//Start returns the first genome position covered by a feature.
+func (f Feature) Start() int {
+	return f.Segments[0].Start
+}
+
+//End returns the last genome position covered by a feature.
+func (f Feature) End() int {
+	return f.Segments[len(f.Segments)-1].End
+}
+
+//Length returns the total number of positions in the segments of a feature.
+func (f Feature) Length() int {
+	n := 0
+	for _, s := range f.Segments {
+		n += s.End - s.Start + 1
+	}
+	return n
+}
+
+//Contains returns true if a 1-based genome position lies within one of the feature's segments.
+func (f Feature) Contains(pos int) bool {
+	for _, s := range f.Segments {
+		if pos >= s.Start && pos <= s.End {
+			return true
+		}
+	}
+	return false
+}
+
+//ReadAnnotationFile takes the name of a GFF3 or GenBank file and returns its features.
+//The format is chosen from the file's first line.
+func ReadAnnotationFile(filename string) ([]Feature, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	firstLine, err := reader.Peek(5)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	var features []Feature
+	if string(firstLine) == "LOCUS" {
+		features, err = ParseGenBankFeatures(reader)
+	} else {
+		features, err = ParseGFF3(reader)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return features, nil
+}
+
+//ParseGFF3 reads features from a GFF3 file. Lines sharing an ID (as the pieces of a
+//spliced or frameshifted CDS do) are merged into one feature with several segments.
+func ParseGFF3(r io.Reader) ([]Feature, error) {
+	features := make([]Feature, 0)
+	indexOfID := make(map[string]int)
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		if line == "##FASTA" {
+			break
+		}
+		if len(strings.TrimSpace(line)) == 0 || line[0] == '#' {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) != 9 {
+			return nil, fmt.Errorf("line %d: expected 9 tab-separated columns, found %d", lineNumber, len(fields))
+		}
+		start, err := strconv.Atoi(fields[3])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: bad start %q", lineNumber, fields[3])
+		}
+		end, err := strconv.Atoi(fields[4])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: bad end %q", lineNumber, fields[4])
+		}
+		if len(fields[6]) != 1 || !strings.Contains("+-.?", fields[6]) {
+			return nil, fmt.Errorf("line %d: bad strand %q", lineNumber, fields[6])
+		}
+		segment := FeatureSegment{start, end}
+
+		attributes := parseGFF3Attributes(fields[8])
+		id := attributes["ID"]
+		key := fields[2] + "\t" + id
+		if i, ok := indexOfID[key]; ok && id != "" {
+			features[i].Segments = append(features[i].Segments, segment)
+			continue
+		}
+
+		f := Feature{
+			SeqID:    fields[0],
+			Type:     fields[2],
+			ID:       id,
+			Name:     attributes["Name"],
+			Gene:     attributes["gene"],
+			Product:  attributes["product"],
+			Strand:   fields[6][0],
+			Segments: []FeatureSegment{segment},
+		}
+		if f.Name == "" {
+			f.Name = f.Gene
+		}
+		indexOfID[key] = len(features)
+		features = append(features, f)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return features, nil
+}
+
+//parseGFF3Attributes splits the ninth column of a GFF3 line into its key=value pairs,
+//undoing percent-encoding.
+func parseGFF3Attributes(column string) map[string]string {
+	attributes := make(map[string]string)
+	for _, pair := range strings.Split(column, ";") {
+		i := strings.Index(pair, "=")
+		if i < 0 {
+			continue
+		}
+		value, err := url.PathUnescape(pair[i+1:])
+		if err != nil {
+			value = pair[i+1:]
+		}
+		attributes[strings.TrimSpace(pair[:i])] = value
+	}
+	return attributes
+}
+
+//ParseGenBankFeatures reads the feature table of a GenBank flat file. Locations may use
+//join(), order(), and complement(), and partial markers such as "<" and ">" are ignored.
+func ParseGenBankFeatures(r io.Reader) ([]Feature, error) {
+	features := make([]Feature, 0)
+	seqID := ""
+	inFeatures := false
+
+	//the feature being read, along with its location and qualifiers, which may span lines
+	var current *Feature
+	location := ""
+	qualifiers := make([]string, 0)
+
+	finish := func() error {
+		if current == nil {
+			return nil
+		}
+		segments, strand, err := parseGenBankLocation(location)
+		if err != nil {
+			return err
+		}
+		current.Segments = segments
+		current.Strand = strand
+		for _, q := range qualifiers {
+			key, value := parseGenBankQualifier(q)
+			switch key {
+			case "gene":
+				current.Gene = value
+			case "product":
+				current.Product = value
+			case "locus_tag":
+				if current.ID == "" {
+					current.ID = value
+				}
+			case "protein_id":
+				current.ID = value
+			}
+		}
+		current.Name = current.Gene
+		if current.Name == "" {
+			current.Name = current.ID
+		}
+		features = append(features, *current)
+		current = nil
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+
+		if strings.HasPrefix(line, "VERSION") || (strings.HasPrefix(line, "ACCESSION") && seqID == "") {
+			if fields := strings.Fields(line); len(fields) > 1 {
+				seqID = fields[1]
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "FEATURES") {
+			inFeatures = true
+			continue
+		}
+		if !inFeatures {
+			continue
+		}
+		if len(line) > 0 && line[0] != ' ' {
+			//ORIGIN or another section ends the feature table
+			break
+		}
+		if len(line) < 21 {
+			continue
+		}
+
+		key := strings.TrimSpace(line[:21])
+		text := strings.TrimSpace(line[21:])
+		if key != "" {
+			//a new feature starts
+			if err := finish(); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+			current = &Feature{SeqID: seqID, Type: key}
+			location = text
+			qualifiers = qualifiers[:0]
+		} else if current == nil {
+			continue
+		} else if strings.HasPrefix(text, "/") {
+			qualifiers = append(qualifiers, text)
+		} else if len(qualifiers) > 0 {
+			//continuation of a long qualifier value
+			qualifiers[len(qualifiers)-1] += " " + text
+		} else {
+			//continuation of a long location
+			location += text
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := finish(); err != nil {
+		return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+	}
+
+	return features, nil
+}
+
+//parseGenBankQualifier splits a qualifier such as /gene="S" into its key and unquoted value.
+func parseGenBankQualifier(q string) (string, string) {
+	q = strings.TrimPrefix(q, "/")
+	i := strings.Index(q, "=")
+	if i < 0 {
+		return q, ""
+	}
+	return q[:i], strings.Trim(q[i+1:], "\"")
+}
+
+//parseGenBankLocation takes a GenBank location such as "join(266..13468,13468..21555)" or
+//"complement(100..200)" and returns its segments in genome order along with its strand.
+func parseGenBankLocation(location string) ([]FeatureSegment, byte, error) {
+	strand := byte('+')
+	location = strings.Replace(location, " ", "", -1)
+	if strings.HasPrefix(location, "complement(") && strings.HasSuffix(location, ")") {
+		strand = '-'
+		location = location[len("complement(") : len(location)-1]
+	}
+	for _, prefix := range []string{"join(", "order("} {
+		if strings.HasPrefix(location, prefix) && strings.HasSuffix(location, ")") {
+			location = location[len(prefix) : len(location)-1]
+		}
+	}
+
+	segments := make([]FeatureSegment, 0)
+	for _, part := range strings.Split(location, ",") {
+		if strings.HasPrefix(part, "complement(") && strings.HasSuffix(part, ")") {
+			strand = '-'
+			part = part[len("complement(") : len(part)-1]
+		}
+		part = strings.NewReplacer("<", "", ">", "").Replace(part)
+
+		bounds := strings.Split(part, "..")
+		start, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, 0, fmt.Errorf("bad location %q", location)
+		}
+		end := start
+		if len(bounds) == 2 {
+			end, err = strconv.Atoi(bounds[1])
+			if err != nil {
+				return nil, 0, fmt.Errorf("bad location %q", location)
+			}
+		}
+		segments = append(segments, FeatureSegment{start, end})
+	}
+
+	//the pieces of a complemented join are listed in reverse; store them in genome order
+	if strand == '-' && len(segments) > 1 && segments[0].Start > segments[len(segments)-1].Start {
+		for i, j := 0, len(segments)-1; i < j; i, j = i+1, j-1 {
+			segments[i], segments[j] = segments[j], segments[i]
+		}
+	}
+
+	return segments, strand, nil
+}
@@ -0,0 +1,205 @@
+package Functions
+
+import "math"
+
+//initialBandWidth is the band width that BandedGlobalAlignment tries first.
+const initialBandWidth = 16
+
+//BandedScoreTable holds global alignment scores only for the nodes (i, j) whose diagonal
+//j - i lies between Lower and Upper. Row i of Scores stores the node (i, j) at index j - i - Lower.
+type BandedScoreTable struct {
+	Lower  int
+	Upper  int
+	Scores [][]float64
+}
+
+//InBand returns true if the node (i, j) lies within the band.
+func (t BandedScoreTable) InBand(i, j int) bool {
+	return j-i >= t.Lower && j-i <= t.Upper
+}
+
+//At returns the score of node (i, j), or minus infinity if the node lies outside the band.
+func (t BandedScoreTable) At(i, j int) float64 {
+	if !t.InBand(i, j) || j < 0 {
+		return math.Inf(-1)
+	}
+	return t.Scores[i][j-i-t.Lower]
+}
+
+//BandedGlobalAlignment takes two strings, along with match, mismatch, and gap scores. It returns
+//a maximum score global alignment of the strings, like GlobalAlignment, but it only fills the
+//table near the diagonal. The band starts narrow and doubles until its score is provably optimal.
+func BandedGlobalAlignment(str0, str1 string, match, mismatch, gap float64) Alignment {
+	return bandedGlobalAlignment(str0, str1, matchMismatch{match, mismatch}, gap)
+}
+
+//BandedGlobalAlignmentWithMatrix is the substitution matrix analogue of BandedGlobalAlignment.
+func BandedGlobalAlignmentWithMatrix(str0, str1 string, scoring ScoringMatrix, gap float64) Alignment {
+	return bandedGlobalAlignment(str0, str1, scoring, gap)
+}
+
+func bandedGlobalAlignment(str0, str1 string, scorer symbolScorer, gap float64) Alignment {
+	n := len(str0)
+	m := len(str1)
+
+	k := initialBandWidth
+	for {
+		scoreTable := bandedGlobalScoreTable(str0, str1, scorer, gap, k)
+
+		//once the band covers the whole table, or no path leaving the band can beat
+		//the best path inside it, we are done
+		if k >= Max(n, m) || scoreTable.At(n, m) >= outsideBandBound(n, m, k, scorer.MaxScore(), gap) {
+			backtrack := BandedGlobalBacktrack(str0, str1, scoreTable, gap)
+			return OutputBandedGlobalAlignment(str0, str1, scoreTable, backtrack)
+		}
+
+		k *= 2
+	}
+}
+
+//outsideBandBound returns an upper bound on the score of any global alignment of strings of
+//lengths n and m whose path leaves the band of width k. Such a path must reach a diagonal at
+//least k+1 beyond the band, so it uses at least |m-n| + 2(k+1) gap columns.
+func outsideBandBound(n, m, k int, maxScore, gap float64) float64 {
+	minGaps := m - n
+	if minGaps < 0 {
+		minGaps = -minGaps
+	}
+	minGaps += 2 * (k + 1)
+	maxGaps := n + m
+	if minGaps > maxGaps {
+		//no such path exists
+		return math.Inf(-1)
+	}
+
+	//every pair of gap columns takes the place of one diagonal column, so the bound is
+	//linear in the number of gaps and is largest at one of the extremes
+	bound := func(g int) float64 {
+		return float64(n+m-g)/2*maxScore - float64(g)*gap
+	}
+	return MaxFloat(bound(minGaps), bound(maxGaps))
+}
+
+//BandedGlobalScoreTable takes two strings, alignment penalties, and a band width k. It returns
+//the global alignment scores of the nodes within k diagonals of the band joining the source
+//to the sink.
+func BandedGlobalScoreTable(str0, str1 string, match, mismatch, gap float64, k int) BandedScoreTable {
+	return bandedGlobalScoreTable(str0, str1, matchMismatch{match, mismatch}, gap, k)
+}
+
+func bandedGlobalScoreTable(str0, str1 string, scorer symbolScorer, gap float64, k int) BandedScoreTable {
+	if len(str0) == 0 || len(str1) == 0 {
+		panic("Zero length strings.")
+	}
+
+	numRows := len(str0) + 1
+	numCols := len(str1) + 1
+
+	//the band must contain both the main diagonal and the diagonal of the sink
+	var t BandedScoreTable
+	t.Lower = Min(0, numCols-numRows) - k
+	t.Upper = Max(0, numCols-numRows) + k
+	width := t.Upper - t.Lower + 1
+
+	t.Scores = make([][]float64, numRows)
+	for i := range t.Scores {
+		t.Scores[i] = make([]float64, width)
+	}
+
+	negInf := math.Inf(-1)
+
+	for i := 0; i < numRows; i++ {
+		for d := t.Lower; d <= t.Upper; d++ {
+			j := i + d
+			if j < 0 || j >= numCols {
+				t.Scores[i][d-t.Lower] = negInf
+				continue
+			}
+
+			var value float64
+			if i == 0 {
+				value = float64(j) * (-gap)
+			} else if j == 0 {
+				value = float64(i) * (-gap)
+			} else {
+				upValue := t.At(i-1, j) - gap
+				leftValue := t.At(i, j-1) - gap
+				diagValue := t.At(i-1, j-1) + scorer.Score(str0[i-1], str1[j-1])
+				value = MaxFloat(upValue, leftValue, diagValue)
+			}
+			t.Scores[i][d-t.Lower] = value
+		}
+	}
+
+	return t
+}
+
+//BandedGlobalBacktrack takes two strings, their banded score table, and the gap penalty.
+//It returns backtracking pointers ("UP", "LEFT", "DIAG") indexed in the same way as the
+//table's scores.
+func BandedGlobalBacktrack(str0, str1 string, scoreTable BandedScoreTable, gap float64) [][]string {
+	numRows := len(str0) + 1
+	numCols := len(str1) + 1
+	width := scoreTable.Upper - scoreTable.Lower + 1
+
+	backtrack := make([][]string, numRows)
+	for i := range backtrack {
+		backtrack[i] = make([]string, width)
+	}
+
+	for i := 0; i < numRows; i++ {
+		for d := scoreTable.Lower; d <= scoreTable.Upper; d++ {
+			j := i + d
+			if j < 0 || j >= numCols || (i == 0 && j == 0) {
+				continue
+			}
+
+			index := d - scoreTable.Lower
+			value := scoreTable.Scores[i][index]
+			if i == 0 || (j > 0 && value == scoreTable.At(i, j-1)-gap) {
+				backtrack[i][index] = "LEFT"
+			} else if j == 0 || value == scoreTable.At(i-1, j)-gap {
+				backtrack[i][index] = "UP"
+			} else {
+				backtrack[i][index] = "DIAG"
+			}
+		}
+	}
+
+	return backtrack
+}
+
+//OutputBandedGlobalAlignment takes two strings, their banded score table, and banded
+//backtracking pointers. It returns the corresponding global alignment.
+func OutputBandedGlobalAlignment(str0, str1 string, scoreTable BandedScoreTable, backtrack [][]string) Alignment {
+	//build the rows backward as byte slices, since genomes make string prepending slow
+	row0 := make([]byte, 0, len(str0)+len(str1))
+	row1 := make([]byte, 0, len(str0)+len(str1))
+
+	i := len(str0)
+	j := len(str1)
+	for i > 0 || j > 0 {
+		pointer := backtrack[i][j-i-scoreTable.Lower]
+		if pointer == "UP" {
+			row0 = append(row0, str0[i-1])
+			row1 = append(row1, '-')
+			i--
+		} else if pointer == "LEFT" {
+			row0 = append(row0, '-')
+			row1 = append(row1, str1[j-1])
+			j--
+		} else if pointer == "DIAG" {
+			row0 = append(row0, str0[i-1])
+			row1 = append(row1, str1[j-1])
+			i--
+			j--
+		} else {
+			panic("Illegal backtracking pointer.")
+		}
+	}
+
+	var a Alignment
+	a[0] = reverseString(string(row0))
+	a[1] = reverseString(string(row1))
+	return a
+}
@@ -0,0 +1,86 @@
+package Functions
+
+//bitParallelThreshold is the size of the edit matrix (rows times columns) at or above which
+//EditDistance switches from filling EditMatrix to BitParallelEditDistance.
+const bitParallelThreshold = 1 << 12
+
+//BitParallelEditDistance takes two strings and returns their Levenshtein distance, just like
+//EditDistance, using Myers' bit-vector algorithm in Hyyrö's blocked form. The columns of the
+//edit matrix are computed 64 rows at a time, one machine word per block of rows, so the
+//distance takes O(nm/64) time and O(m) memory for strings of lengths n and m.
+func BitParallelEditDistance(str1, str2 string) int {
+	//the shorter string runs down the rows, so that there are fewer blocks
+	if len(str1) < len(str2) {
+		str1, str2 = str2, str1
+	}
+	pattern, text := str2, str1
+	m := len(pattern)
+	if m == 0 {
+		return len(text)
+	}
+
+	numBlocks := (m + 63) / 64
+	lastBit := uint64(1) << uint((m-1)%64) // bit of the last row of the last block
+
+	//peq[b][c] has a bit set for each row of block b whose pattern symbol is c
+	peq := make([][256]uint64, numBlocks)
+	for i := 0; i < m; i++ {
+		peq[i/64][pattern[i]] |= 1 << uint(i%64)
+	}
+
+	//vertical differences down column 0 are all +1, since D[i][0] = i
+	pv := make([]uint64, numBlocks)
+	mv := make([]uint64, numBlocks)
+	for b := range pv {
+		pv[b] = ^uint64(0)
+	}
+
+	score := m // D[m][j] for the current column j
+	for j := 0; j < len(text); j++ {
+		c := text[j]
+		//D[0][j] - D[0][j-1] = +1 enters the top of the first block
+		hin := 1
+		for b := 0; b < numBlocks; b++ {
+			highBit := uint64(1) << 63
+			if b == numBlocks-1 {
+				highBit = lastBit
+			}
+			hin = advanceBlock(&pv[b], &mv[b], peq[b][c], hin, highBit)
+		}
+		score += hin
+	}
+
+	return score
+}
+
+//advanceBlock moves one block of rows of the edit matrix forward by one column. It takes the
+//block's vertical difference vectors, its match vector for the column's symbol, and the
+//horizontal difference entering at the top of the block. It updates the vectors and returns
+//the horizontal difference at the row marked by highBit, which leaves the bottom of the block.
+func advanceBlock(pv, mv *uint64, eq uint64, hin int, highBit uint64) int {
+	xv := eq | *mv
+	if hin < 0 {
+		eq |= 1
+	}
+	xh := (((eq & *pv) + *pv) ^ *pv) | eq
+	ph := *mv | ^(xh | *pv)
+	mh := *pv & xh
+
+	hout := 0
+	if ph&highBit != 0 {
+		hout = 1
+	} else if mh&highBit != 0 {
+		hout = -1
+	}
+
+	ph <<= 1
+	mh <<= 1
+	if hin < 0 {
+		mh |= 1
+	} else if hin > 0 {
+		ph |= 1
+	}
+	*pv = mh | ^(xv | ph)
+	*mv = ph & xv
+	return hout
+}
@@ -0,0 +1,57 @@
+package Functions
+
+import "strconv"
+
+//CIGAROperation is a run of alignment columns of the same kind, as in the CIGAR strings of
+//SAM files. Op is '=' (match), 'X' (mismatch), 'I' (symbol of str1 against a gap), 'D' (symbol
+//of str0 against a gap), or 'S' (symbols of str1 clipped from the alignment).
+type CIGAROperation struct {
+	Op     byte
+	Length int
+}
+
+//AlignmentCIGAR takes an alignment whose top row (str0) is the reference and whose bottom
+//row (str1) is the query. It returns the alignment's CIGAR operations. Columns in which both
+//rows hold a gap are skipped.
+func AlignmentCIGAR(a Alignment) []CIGAROperation {
+	if len(a[0]) != len(a[1]) {
+		panic("Error: alignment rows have different lengths.")
+	}
+
+	ops := make([]CIGAROperation, 0)
+	for i := 0; i < len(a[0]); i++ {
+		var op byte
+		if a[0][i] == '-' && a[1][i] == '-' {
+			continue
+		} else if a[0][i] == '-' {
+			op = 'I'
+		} else if a[1][i] == '-' {
+			op = 'D'
+		} else if a[0][i] == a[1][i] {
+			op = '='
+		} else {
+			op = 'X'
+		}
+
+		//extend the last run or start a new one
+		if len(ops) > 0 && ops[len(ops)-1].Op == op {
+			ops[len(ops)-1].Length++
+		} else {
+			ops = append(ops, CIGAROperation{op, 1})
+		}
+	}
+	return ops
+}
+
+//CIGARString takes a collection of CIGAR operations and returns them as a CIGAR string
+//such as "10=1X2D5=". An empty collection is written as "*".
+func CIGARString(ops []CIGAROperation) string {
+	if len(ops) == 0 {
+		return "*"
+	}
+	s := ""
+	for _, op := range ops {
+		s += strconv.Itoa(op.Length) + string(op.Op)
+	}
+	return s
+}
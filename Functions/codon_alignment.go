@@ -0,0 +1,64 @@
+package Functions
+
+import "strings"
+
+//CDSAlignment takes two coding sequences, a genetic code, a protein substitution matrix such as
+//BLOSUM62, and a gap penalty per amino acid. Each coding sequence must be a single in-frame
+//CDS, such as an ORF from FindORFs: a whole number of codons read from its first symbol, with
+//no stop codon except possibly the last. It translates the sequences, globally aligns the
+//proteins, and threads the protein alignment's gaps back onto the DNA, so that every gap in
+//the result is a whole number of codons.
+func CDSAlignment(cds0, cds1 string, code GeneticCode, scoring ScoringMatrix, gap float64) Alignment {
+	protein0 := translateCDS(cds0, code)
+	protein1 := translateCDS(cds1, code)
+
+	proteinAlignment := GlobalAlignmentWithMatrix(protein0, protein1, scoring, gap)
+	return ThreadProteinAlignment(proteinAlignment, cds0, cds1)
+}
+
+//translateCDS translates a coding sequence for CDSAlignment, panicking if it isn't a whole
+//number of codons or has a stop codon before its last codon.
+func translateCDS(cds string, code GeneticCode) string {
+	if len(cds) == 0 || len(cds)%3 != 0 {
+		panic("Error: coding sequences must be a whole number of codons.")
+	}
+	protein := code.Translate(cds)
+	if i := strings.IndexByte(protein, '*'); i >= 0 && i < len(protein)-1 {
+		panic("Error: coding sequence has a stop codon before its end.")
+	}
+	return protein
+}
+
+//ThreadProteinAlignment takes an alignment of two proteins and the coding DNA strings that
+//encode them. It returns the DNA alignment in which each amino acid is replaced by its codon
+//and each protein gap by three gap symbols. Any incomplete codons left at the ends of the DNA
+//strings are aligned against gaps at the end.
+func ThreadProteinAlignment(proteinAlignment Alignment, dna0, dna1 string) Alignment {
+	var rows [2][]byte
+	dna := [2]string{dna0, dna1}
+	next := [2]int{0, 0} // index in each DNA string of the next unused codon
+
+	for col := 0; col < len(proteinAlignment[0]); col++ {
+		for r := 0; r < 2; r++ {
+			if proteinAlignment[r][col] == '-' {
+				rows[r] = append(rows[r], '-', '-', '-')
+				continue
+			}
+			if next[r]+3 > len(dna[r]) {
+				panic("Error: protein is longer than its coding sequence.")
+			}
+			rows[r] = append(rows[r], dna[r][next[r]:next[r]+3]...)
+			next[r] += 3
+		}
+	}
+
+	//leftover symbols that don't make a whole codon
+	leftover0 := dna0[next[0]:]
+	leftover1 := dna1[next[1]:]
+	rows[0] = append(rows[0], leftover0...)
+	rows[0] = append(rows[0], gapString(len(leftover1))...)
+	rows[1] = append(rows[1], gapString(len(leftover0))...)
+	rows[1] = append(rows[1], leftover1...)
+
+	return Alignment{string(rows[0]), string(rows[1])}
+}
@@ -0,0 +1,133 @@
+package Functions
+
+//OSADistance takes two strings as input. It returns their optimal string alignment distance:
+//the minimum number of substitutions, insertions, deletions, and transpositions of adjacent
+//symbols needed to transform one string into the other, when no substring is edited twice.
+func OSADistance(str1, str2 string) int {
+	return OSAMatrix(str1, str2)[len(str1)][len(str2)]
+}
+
+//OSAMatrix is like EditMatrix, but its (i, j)-th value is the optimal string alignment
+//distance between the first i symbols of str1 and the first j symbols of str2.
+func OSAMatrix(str1, str2 string) [][]int {
+	scoringMatrix := unitEditTable(len(str1), len(str2))
+
+	for row := 1; row <= len(str1); row++ {
+		for col := 1; col <= len(str2); col++ {
+			diag := scoringMatrix[row-1][col-1]
+			if str1[row-1] != str2[col-1] {
+				diag++
+			}
+			scoringMatrix[row][col] = Min(scoringMatrix[row-1][col]+1, scoringMatrix[row][col-1]+1, diag)
+
+			//swapping the last two symbols of one prefix gives the last two of the other
+			if row > 1 && col > 1 && str1[row-1] == str2[col-2] && str1[row-2] == str2[col-1] {
+				scoringMatrix[row][col] = Min(scoringMatrix[row][col], scoringMatrix[row-2][col-2]+1)
+			}
+		}
+	}
+	return scoringMatrix
+}
+
+//OSADistanceMatrix is like EditDistanceMatrix, but uses the optimal string alignment distance.
+func OSADistanceMatrix(patterns []string) [][]int {
+	return pairwiseIntMatrix(patterns, OSADistance)
+}
+
+//DamerauLevenshteinDistance takes two strings as input. It returns the minimum number of
+//substitutions, insertions, deletions, and transpositions of adjacent symbols needed to
+//transform one string into the other. Unlike OSADistance, symbols may be edited again after
+//being transposed, so that "CA" becomes "ABC" in two edits (CA -> AC -> ABC) rather than three.
+func DamerauLevenshteinDistance(str1, str2 string) int {
+	return DamerauLevenshteinMatrix(str1, str2)[len(str1)][len(str2)]
+}
+
+//DamerauLevenshteinMatrix is like EditMatrix, but its (i, j)-th value is the
+//Damerau-Levenshtein distance between the first i symbols of str1 and the first j symbols of str2.
+//It uses the algorithm of Lowrance and Wagner.
+func DamerauLevenshteinMatrix(str1, str2 string) [][]int {
+	n, m := len(str1), len(str2)
+	maxDist := n + m
+
+	//d has an extra row and column at the top left holding maxDist, so that d[i+1][j+1] is the
+	//distance between the first i symbols of str1 and the first j symbols of str2
+	d := make([][]int, n+2)
+	for i := range d {
+		d[i] = make([]int, m+2)
+		d[i][0] = maxDist
+	}
+	for j := range d[0] {
+		d[0][j] = maxDist
+	}
+	for i := 0; i <= n; i++ {
+		d[i+1][1] = i
+	}
+	for j := 0; j <= m; j++ {
+		d[1][j+1] = j
+	}
+
+	//lastRow[c] is the last row (1-based) of str1 holding symbol c so far
+	var lastRow [256]int
+
+	for i := 1; i <= n; i++ {
+		lastMatchCol := 0 // last column (1-based) in this row where str2 matched str1[i-1]
+		for j := 1; j <= m; j++ {
+			k := lastRow[str2[j-1]]
+			l := lastMatchCol
+			cost := 1
+			if str1[i-1] == str2[j-1] {
+				cost = 0
+				lastMatchCol = j
+			}
+			d[i+1][j+1] = Min(
+				d[i][j]+cost,              // substitution or match
+				d[i+1][j]+1,               // insertion
+				d[i][j+1]+1,               // deletion
+				d[k][l]+(i-k-1)+1+(j-l-1), // transposition, with the symbols between deleted or inserted
+			)
+		}
+		lastRow[str1[i-1]] = i
+	}
+
+	//drop the extra row and column
+	scoringMatrix := make([][]int, n+1)
+	for i := range scoringMatrix {
+		scoringMatrix[i] = d[i+1][1:]
+	}
+	return scoringMatrix
+}
+
+//DamerauLevenshteinDistanceMatrix is like EditDistanceMatrix, but uses the Damerau-Levenshtein distance.
+func DamerauLevenshteinDistanceMatrix(patterns []string) [][]int {
+	return pairwiseIntMatrix(patterns, DamerauLevenshteinDistance)
+}
+
+//unitEditTable makes an edit table for strings of lengths n and m whose 0-th row and column
+//hold the costs of inserting or deleting every symbol of a prefix.
+func unitEditTable(n, m int) [][]int {
+	table := make([][]int, n+1)
+	for i := range table {
+		table[i] = make([]int, m+1)
+		table[i][0] = i
+	}
+	for j := range table[0] {
+		table[0][j] = j
+	}
+	return table
+}
+
+//pairwiseIntMatrix returns the symmetric matrix of a distance between every pair of strings.
+func pairwiseIntMatrix(patterns []string, distance func(string, string) int) [][]int {
+	mtx := make([][]int, len(patterns))
+	for i := range mtx {
+		mtx[i] = make([]int, len(patterns))
+	}
+	for i := range patterns {
+		for j := i + 1; j < len(patterns); j++ {
+			d := distance(patterns[i], patterns[j])
+			mtx[i][j] = d
+			mtx[j][i] = d
+		}
+	}
+	return mtx
+}
@@ -0,0 +1,85 @@
+package Functions
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+//DistanceMatrix is a square matrix whose (i,j)-th value is the distance between the i-th
+//and j-th objects, such as species or sequences.
+type DistanceMatrix [][]float64
+
+//IntToDistanceMatrix converts a matrix of integers, such as the one returned by
+//EditDistanceMatrix, to a DistanceMatrix.
+func IntToDistanceMatrix(mtx [][]int) DistanceMatrix {
+	d := make(DistanceMatrix, len(mtx))
+	for i := range mtx {
+		d[i] = make([]float64, len(mtx[i]))
+		for j := range mtx[i] {
+			d[i][j] = float64(mtx[i][j])
+		}
+	}
+	return d
+}
+
+//ReadDistanceMatrix reads a distance matrix and a slice of labels from a file. The first line
+//of the file holds the number of labels, and each other line holds a label followed by its
+//tab-separated distances to every label.
+func ReadDistanceMatrix(filename string) (DistanceMatrix, []string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, fmt.Errorf("%s: empty distance matrix file", filename)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	if err != nil || n < 0 {
+		return nil, nil, fmt.Errorf("%s line 1: expected the number of labels, got %q", filename, scanner.Text())
+	}
+
+	mtx := make(DistanceMatrix, 0, n)
+	labels := make([]string, 0, n)
+	lineNumber := 1
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != n+1 {
+			return nil, nil, fmt.Errorf("%s line %d: expected a label and %d distances, got %d fields", filename, lineNumber, n, len(fields))
+		}
+		row := make([]float64, n)
+		for j := range row {
+			row[j], err = strconv.ParseFloat(strings.TrimSpace(fields[j+1]), 64)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s line %d: %v", filename, lineNumber, err)
+			}
+			//infinite or negative distances would make a meaningless tree
+			if math.IsInf(row[j], 0) || math.IsNaN(row[j]) || row[j] < 0 {
+				return nil, nil, fmt.Errorf("%s line %d: distance %q is not a finite, non-negative number", filename, lineNumber, fields[j+1])
+			}
+		}
+		labels = append(labels, fields[0])
+		mtx = append(mtx, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	if len(mtx) != n {
+		return nil, nil, fmt.Errorf("%s: expected %d rows, got %d", filename, n, len(mtx))
+	}
+	return mtx, labels, nil
+}
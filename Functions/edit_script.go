@@ -0,0 +1,221 @@
+package Functions
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//EditKind names one of the three edits counted by the edit distance.
+type EditKind string
+
+const (
+	EditSubstitution EditKind = "substitution"
+	EditInsertion    EditKind = "insertion"
+	EditDeletion     EditKind = "deletion"
+)
+
+//EditOperation is one edit transforming str1 into str2. Pos1 and Pos2 are 0-based indices into
+//str1 and str2: a substitution replaces str1[Pos1] (Symbol1) with str2[Pos2] (Symbol2), a
+//deletion removes str1[Pos1], and an insertion adds str2[Pos2] just before str1[Pos1] (or at
+//the end if Pos1 = len(str1)). The unused symbol of an insertion or deletion is "-".
+type EditOperation struct {
+	Kind    EditKind
+	Pos1    int
+	Pos2    int
+	Symbol1 byte
+	Symbol2 byte
+}
+
+//String writes an edit operation with 1-based positions, such as "substitution 5 A>G".
+func (op EditOperation) String() string {
+	switch op.Kind {
+	case EditSubstitution:
+		return fmt.Sprintf("%s %d %c>%c", op.Kind, op.Pos1+1, op.Symbol1, op.Symbol2)
+	case EditDeletion:
+		return fmt.Sprintf("%s %d %c", op.Kind, op.Pos1+1, op.Symbol1)
+	}
+	return fmt.Sprintf("%s %d %c", op.Kind, op.Pos2+1, op.Symbol2)
+}
+
+//EditScript takes two strings and returns a shortest list of edits transforming str1 into
+//str2, in order from the start of the strings, found by tracing back through EditMatrix.
+//The number of edits is EditDistance(str1, str2).
+func EditScript(str1, str2 string) []EditOperation {
+	script := make([]EditOperation, 0)
+	if len(str1) == 0 || len(str2) == 0 {
+		//EditMatrix needs nonempty strings, and every symbol is an insertion or a deletion
+		for i := 0; i < len(str1); i++ {
+			script = append(script, EditOperation{EditDeletion, i, 0, str1[i], '-'})
+		}
+		for j := 0; j < len(str2); j++ {
+			script = append(script, EditOperation{EditInsertion, 0, j, '-', str2[j]})
+		}
+		return script
+	}
+
+	mtx := EditMatrix(str1, str2)
+
+	//start at the bottom right and work backward, preferring matches and substitutions
+	row, col := len(str1), len(str2)
+	for row > 0 || col > 0 {
+		if row > 0 && col > 0 && str1[row-1] == str2[col-1] && mtx[row][col] == mtx[row-1][col-1] {
+			row--
+			col--
+		} else if row > 0 && col > 0 && mtx[row][col] == mtx[row-1][col-1]+1 {
+			row--
+			col--
+			script = append(script, EditOperation{EditSubstitution, row, col, str1[row], str2[col]})
+		} else if row > 0 && mtx[row][col] == mtx[row-1][col]+1 {
+			row--
+			script = append(script, EditOperation{EditDeletion, row, col, str1[row], '-'})
+		} else if col > 0 && mtx[row][col] == mtx[row][col-1]+1 {
+			col--
+			script = append(script, EditOperation{EditInsertion, row, col, '-', str2[col]})
+		} else {
+			panic("Error: edit matrix is inconsistent.")
+		}
+	}
+
+	//the edits were found backward
+	for i, j := 0, len(script)-1; i < j; i, j = i+1, j-1 {
+		script[i], script[j] = script[j], script[i]
+	}
+	return script
+}
+
+//AlignmentEditScript takes an alignment of str1 (top row) and str2 (bottom row) and returns
+//the edits it implies: mismatched columns are substitutions, a gap in the bottom row is a
+//deletion, and a gap in the top row is an insertion. For an alignment maximizing the score
+//with a match reward of 0 and mismatch and gap penalties of 1, such as one found in linear
+//space by GlobalAlignmentLinearSpace, this is a shortest edit script.
+func AlignmentEditScript(a Alignment) []EditOperation {
+	if len(a[0]) != len(a[1]) {
+		panic("Error: alignment rows have different lengths.")
+	}
+
+	script := make([]EditOperation, 0)
+	pos1, pos2 := 0, 0
+	for i := 0; i < len(a[0]); i++ {
+		switch {
+		case a[0][i] == '-' && a[1][i] == '-':
+			continue
+		case a[0][i] == '-':
+			script = append(script, EditOperation{EditInsertion, pos1, pos2, '-', a[1][i]})
+			pos2++
+		case a[1][i] == '-':
+			script = append(script, EditOperation{EditDeletion, pos1, pos2, a[0][i], '-'})
+			pos1++
+		default:
+			if a[0][i] != a[1][i] {
+				script = append(script, EditOperation{EditSubstitution, pos1, pos2, a[0][i], a[1][i]})
+			}
+			pos1++
+			pos2++
+		}
+	}
+	return script
+}
+
+//ApplyEditScript takes a string and a list of edits in the order returned by EditScript.
+//It returns the string produced by applying the edits, or an error if an edit doesn't fit
+//the string: its position is out of order or its symbol isn't the one found there.
+func ApplyEditScript(str1 string, script []EditOperation) (string, error) {
+	var b strings.Builder
+	next := 0 // index in str1 of the first symbol not yet copied or edited
+
+	for k, op := range script {
+		if op.Pos1 < next || op.Pos1 > len(str1) {
+			return "", fmt.Errorf("edit %d (%v): position out of order or past the end of the string", k+1, op)
+		}
+		//copy the unchanged symbols before the edit
+		b.WriteString(str1[next:op.Pos1])
+		next = op.Pos1
+		if op.Pos2 != b.Len() {
+			return "", fmt.Errorf("edit %d (%v): expected position %d in the new string", k+1, op, b.Len()+1)
+		}
+
+		switch op.Kind {
+		case EditSubstitution, EditDeletion:
+			if op.Pos1 == len(str1) || str1[op.Pos1] != op.Symbol1 {
+				return "", fmt.Errorf("edit %d (%v): symbol %c not found", k+1, op, op.Symbol1)
+			}
+			if op.Kind == EditSubstitution {
+				b.WriteByte(op.Symbol2)
+			}
+			next++
+		case EditInsertion:
+			b.WriteByte(op.Symbol2)
+		default:
+			return "", fmt.Errorf("edit %d: unknown kind %q", k+1, op.Kind)
+		}
+	}
+
+	b.WriteString(str1[next:])
+	return b.String(), nil
+}
+
+//maxDiffContext is the longest run of unchanged symbols that EditDiff writes out in full.
+const maxDiffContext = 20
+
+//EditDiff takes a string and an edit script transforming it into str2, and returns a
+//diff-style listing of the script. Each line starts with " " for unchanged symbols, "-" for symbols of
+//str1 that are deleted or substituted, or "+" for symbols of str2 that are inserted or
+//substituted in, followed by the 1-based positions of the run and its symbols. Long runs of
+//unchanged symbols are summarized by their length.
+func EditDiff(str1 string, script []EditOperation) string {
+	var b strings.Builder
+	next1 := 0 // index in str1 of the first symbol not yet listed
+
+	//writeRun writes one line for a run of symbols starting at a 0-based position
+	writeRun := func(prefix byte, start int, symbols string) {
+		b.WriteByte(prefix)
+		b.WriteByte(' ')
+		b.WriteString(strconv.Itoa(start + 1))
+		if len(symbols) > 1 {
+			b.WriteString("-" + strconv.Itoa(start+len(symbols)))
+		}
+		if prefix == ' ' && len(symbols) > maxDiffContext {
+			b.WriteString(" (" + strconv.Itoa(len(symbols)) + " unchanged symbols)\n")
+			return
+		}
+		b.WriteString(" " + symbols + "\n")
+	}
+
+	for k := 0; k < len(script); {
+		op := script[k]
+		if op.Pos1 < next1 || op.Pos1 > len(str1) {
+			panic("Error: edit script is out of order.")
+		}
+		if op.Pos1 > next1 {
+			writeRun(' ', next1, str1[next1:op.Pos1])
+			next1 = op.Pos1
+		}
+
+		//gather the block of adjacent edits starting here
+		var deleted, inserted []byte
+		start2 := op.Pos2
+		for k < len(script) && script[k].Pos1 == next1 {
+			op = script[k]
+			if op.Kind != EditInsertion {
+				deleted = append(deleted, op.Symbol1)
+				next1++
+			}
+			if op.Kind != EditDeletion {
+				inserted = append(inserted, op.Symbol2)
+			}
+			k++
+		}
+		if len(deleted) > 0 {
+			writeRun('-', next1-len(deleted), string(deleted))
+		}
+		if len(inserted) > 0 {
+			writeRun('+', start2, string(inserted))
+		}
+	}
+	if next1 < len(str1) {
+		writeRun(' ', next1, str1[next1:])
+	}
+
+	return b.String()
+}
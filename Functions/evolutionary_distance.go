@@ -0,0 +1,314 @@
+package Functions
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+//Errors returned by the distance functions when an alignment doesn't hold enough information
+//for a distance to be estimated. Distances that correct for multiple substitutions at a site
+//are infinite once the sequences are too different for the model to explain, and these
+//functions return ErrSaturated rather than an infinite distance that would spoil a tree.
+var (
+	ErrNoSites            = errors.New("alignment has no sites to compare")
+	ErrSaturated          = errors.New("sequences are too divergent for the model")
+	ErrMissingNucleotides = errors.New("Tamura-Nei distance needs all four nucleotides to be present")
+)
+
+//PDistance takes an alignment and returns the proportion of its sites that differ. Sites
+//with a gap in either row are skipped, and symbols are compared ignoring case.
+func PDistance(a Alignment) (float64, error) {
+	sites, differences := 0, 0
+	for i := 0; i < len(a[0]) && i < len(a[1]); i++ {
+		if a[0][i] == '-' || a[1][i] == '-' {
+			continue
+		}
+		sites++
+		if upperSymbol(a[0][i]) != upperSymbol(a[1][i]) {
+			differences++
+		}
+	}
+	if sites == 0 {
+		return 0, ErrNoSites
+	}
+	return float64(differences) / float64(sites), nil
+}
+
+//JukesCantorDistance takes an alignment of DNA strings and returns the Jukes-Cantor (JC69)
+//estimate of the number of substitutions per site, which assumes that every substitution
+//is equally likely.
+func JukesCantorDistance(a Alignment) (float64, error) {
+	c := NucleotideSubstitutions(a)
+	if c.Sites == 0 {
+		return 0, ErrNoSites
+	}
+	return correctedLog(1.0-4.0/3.0*c.Proportion(), -3.0/4.0)
+}
+
+//SubstitutionCounts summarizes the sites of an alignment of DNA strings that hold
+//unambiguous nucleotides (A, C, G, and T or U) in both rows. Transitions are changes between
+//two purines (A and G) or two pyrimidines (C and T), and transversions are all other changes.
+//BaseCounts holds the number of A, C, G, and T symbols over both rows of these sites.
+type SubstitutionCounts struct {
+	Sites                 int
+	PurineTransitions     int
+	PyrimidineTransitions int
+	Transversions         int
+	BaseCounts            [4]int
+}
+
+//Transitions returns the number of sites holding a transition.
+func (c SubstitutionCounts) Transitions() int {
+	return c.PurineTransitions + c.PyrimidineTransitions
+}
+
+//Differences returns the number of sites holding any substitution.
+func (c SubstitutionCounts) Differences() int {
+	return c.Transitions() + c.Transversions
+}
+
+//Proportion returns the proportion of sites holding a substitution (the p-distance), or NaN
+//if there are no sites.
+func (c SubstitutionCounts) Proportion() float64 {
+	return float64(c.Differences()) / float64(c.Sites)
+}
+
+//NucleotideSubstitutions takes an alignment of DNA strings and counts the transitions and
+//transversions at the sites holding unambiguous nucleotides in both rows. Sites is zero if
+//there are no such sites.
+func NucleotideSubstitutions(a Alignment) SubstitutionCounts {
+	var c SubstitutionCounts
+	for i := 0; i < len(a[0]) && i < len(a[1]); i++ {
+		//nucleotideIndex orders nucleotides T, C, A, G
+		x := nucleotideIndex(a[0][i])
+		y := nucleotideIndex(a[1][i])
+		if x < 0 || y < 0 {
+			continue
+		}
+		c.Sites++
+		c.BaseCounts[acgtIndex(x)]++
+		c.BaseCounts[acgtIndex(y)]++
+		if x == y {
+			continue
+		}
+		if x+y == 5 {
+			//A (2) and G (3)
+			c.PurineTransitions++
+		} else if x+y == 1 {
+			//T (0) and C (1)
+			c.PyrimidineTransitions++
+		} else {
+			c.Transversions++
+		}
+	}
+	return c
+}
+
+//acgtIndex converts an index from nucleotideIndex into the order A, C, G, T used by BaseCounts.
+func acgtIndex(tcag int) int {
+	return [4]int{3, 1, 0, 2}[tcag]
+}
+
+//Kimura2PDistance takes an alignment of DNA strings and returns the Kimura two-parameter (K80)
+//estimate of the number of substitutions per site, which lets transitions and transversions
+//occur at different rates.
+func Kimura2PDistance(a Alignment) (float64, error) {
+	c := NucleotideSubstitutions(a)
+	if c.Sites == 0 {
+		return 0, ErrNoSites
+	}
+	P := float64(c.Transitions()) / float64(c.Sites)
+	Q := float64(c.Transversions) / float64(c.Sites)
+	return sumCorrectedLogs([2]float64{1 - 2*P - Q, -0.5}, [2]float64{1 - 2*Q, -0.25})
+}
+
+//TamuraNeiDistance takes an alignment of DNA strings and returns the Tamura-Nei (TN93) estimate
+//of the number of substitutions per site. It allows unequal base frequencies, estimated from
+//both rows, and separate rates for purine transitions, pyrimidine transitions, and transversions.
+func TamuraNeiDistance(a Alignment) (float64, error) {
+	c := NucleotideSubstitutions(a)
+	if c.Sites == 0 {
+		return 0, ErrNoSites
+	}
+	total := float64(2 * c.Sites)
+	gA := float64(c.BaseCounts[0]) / total
+	gC := float64(c.BaseCounts[1]) / total
+	gG := float64(c.BaseCounts[2]) / total
+	gT := float64(c.BaseCounts[3]) / total
+	if gA*gC*gG*gT == 0 {
+		return 0, ErrMissingNucleotides
+	}
+	gR := gA + gG
+	gY := gC + gT
+
+	P1 := float64(c.PurineTransitions) / float64(c.Sites)
+	P2 := float64(c.PyrimidineTransitions) / float64(c.Sites)
+	Q := float64(c.Transversions) / float64(c.Sites)
+
+	return sumCorrectedLogs(
+		[2]float64{1 - gR*P1/(2*gA*gG) - Q/(2*gR), -2 * gA * gG / gR},
+		[2]float64{1 - gY*P2/(2*gC*gT) - Q/(2*gY), -2 * gC * gT / gY},
+		[2]float64{1 - Q/(2*gR*gY), -2 * (gR*gY - gA*gG*gY/gR - gC*gT*gR/gY)})
+}
+
+//PoissonDistance takes an alignment of proteins and returns the Poisson-corrected number of
+//amino acid substitutions per site.
+func PoissonDistance(a Alignment) (float64, error) {
+	p, err := PDistance(a)
+	if err != nil {
+		return 0, err
+	}
+	return correctedLog(1-p, -1)
+}
+
+//KimuraProteinDistance takes an alignment of proteins and returns Kimura's empirical estimate
+//of the number of amino acid substitutions per site, -ln(1 - p - 0.2p^2).
+func KimuraProteinDistance(a Alignment) (float64, error) {
+	p, err := PDistance(a)
+	if err != nil {
+		return 0, err
+	}
+	return correctedLog(1-p-0.2*p*p, -1)
+}
+
+//correctedLog returns coefficient * ln(x), or ErrSaturated if x isn't positive because the
+//sequences are saturated with substitutions.
+func correctedLog(x, coefficient float64) (float64, error) {
+	if x <= 0 {
+		return 0, ErrSaturated
+	}
+	return coefficient * math.Log(x), nil
+}
+
+//sumCorrectedLogs takes pairs of arguments to correctedLog and returns the sum of the results,
+//or ErrSaturated if any of them is saturated.
+func sumCorrectedLogs(terms ...[2]float64) (float64, error) {
+	sum := 0.0
+	for _, term := range terms {
+		d, err := correctedLog(term[0], term[1])
+		if err != nil {
+			return 0, err
+		}
+		sum += d
+	}
+	return sum, nil
+}
+
+//DistanceModel names a way of turning a pairwise alignment into an evolutionary distance.
+type DistanceModel int
+
+const (
+	PDistanceModel DistanceModel = iota
+	JukesCantorModel
+	Kimura2PModel
+	TamuraNeiModel
+	PoissonModel
+	KimuraProteinModel
+)
+
+//String returns the usual short name of a distance model.
+func (m DistanceModel) String() string {
+	switch m {
+	case PDistanceModel:
+		return "p-distance"
+	case JukesCantorModel:
+		return "JC69"
+	case Kimura2PModel:
+		return "K80"
+	case TamuraNeiModel:
+		return "TN93"
+	case PoissonModel:
+		return "Poisson"
+	case KimuraProteinModel:
+		return "Kimura"
+	}
+	return "unknown"
+}
+
+//Distance returns the distance between the rows of an alignment under the model, or an error
+//such as ErrSaturated if the model can't estimate it.
+func (m DistanceModel) Distance(a Alignment) (float64, error) {
+	switch m {
+	case PDistanceModel:
+		return PDistance(a)
+	case JukesCantorModel:
+		return JukesCantorDistance(a)
+	case Kimura2PModel:
+		return Kimura2PDistance(a)
+	case TamuraNeiModel:
+		return TamuraNeiDistance(a)
+	case PoissonModel:
+		return PoissonDistance(a)
+	case KimuraProteinModel:
+		return KimuraProteinDistance(a)
+	}
+	return 0, fmt.Errorf("unknown distance model %d", int(m))
+}
+
+//ModelDistanceMatrix takes a slice of strings, a distance model, and match, mismatch, and gap
+//scores. It globally aligns every pair of strings with these scores and returns a matrix whose
+//(i,j)-th value is the distance between the i-th and j-th strings under the model. If the model
+//can't estimate the distance between some pair, it returns an error naming the pair.
+func ModelDistanceMatrix(patterns []string, model DistanceModel, match, mismatch, gap float64) (DistanceMatrix, error) {
+	return modelDistanceMatrix(patterns, model, func(str0, str1 string) Alignment {
+		return GlobalAlignmentLinearSpace(str0, str1, match, mismatch, gap)
+	})
+}
+
+//ModelDistanceMatrixWithMatrix is like ModelDistanceMatrix, but aligns the strings using a
+//substitution matrix such as BLOSUM62.
+func ModelDistanceMatrixWithMatrix(patterns []string, model DistanceModel, scoring ScoringMatrix, gap float64) (DistanceMatrix, error) {
+	return modelDistanceMatrix(patterns, model, func(str0, str1 string) Alignment {
+		return GlobalAlignmentLinearSpaceWithMatrix(str0, str1, scoring, gap)
+	})
+}
+
+//modelDistanceMatrix fills a distance matrix using a function that aligns two strings.
+func modelDistanceMatrix(patterns []string, model DistanceModel, align func(string, string) Alignment) (DistanceMatrix, error) {
+	mtx := make(DistanceMatrix, len(patterns))
+	for i := range mtx {
+		mtx[i] = make([]float64, len(patterns))
+	}
+
+	for i := range patterns {
+		for j := i + 1; j < len(patterns); j++ {
+			d, err := model.Distance(align(patterns[i], patterns[j]))
+			if err != nil {
+				return nil, pairError(i, j, err)
+			}
+			mtx[i][j] = d
+			mtx[j][i] = d
+		}
+	}
+
+	return mtx, nil
+}
+
+//pairError wraps an error from computing the distance between the i-th and j-th strings.
+func pairError(i, j int, err error) error {
+	return fmt.Errorf("sequences %d and %d: %w", i+1, j+1, err)
+}
+
+//MultipleAlignmentDistanceMatrix returns a matrix whose (i,j)-th value is the distance under
+//a model between rows i and j of a multiple alignment, or an error if the model can't estimate
+//the distance between some pair of rows.
+func MultipleAlignmentDistanceMatrix(ma MultipleAlignment, model DistanceModel) (DistanceMatrix, error) {
+	mtx := make(DistanceMatrix, ma.NumRows())
+	for i := range mtx {
+		mtx[i] = make([]float64, ma.NumRows())
+	}
+
+	for i := 0; i < ma.NumRows(); i++ {
+		for j := i + 1; j < ma.NumRows(); j++ {
+			d, err := model.Distance(ma.Pair(i, j))
+			if err != nil {
+				return nil, pairError(i, j, err)
+			}
+			mtx[i][j] = d
+			mtx[j][i] = d
+		}
+	}
+
+	return mtx, nil
+}
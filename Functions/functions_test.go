@@ -2,16 +2,20 @@ package Functions
 
 import (
 	"bufio"
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"math"
+	"math/rand"
 	"os"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"testing"
 )
 
-type DistanceMatrix [][]float64
-
 /********************************************
  Levenshtein Matrix Tests (Edit-Distance Tests)
 *********************************************/
@@ -52,6 +56,71 @@ func TestEditDistanceMatrix(t *testing.T) {
 	}
 }
 
+func TestEditDistanceMatrixParallel(t *testing.T) {
+	for _, pair := range levenshteinMatrixTests {
+		expected := EditDistanceMatrix(pair.patterns)
+		for workers := 0; workers <= 4; workers++ {
+			calls, lastDone := 0, 0
+			opts := MatrixOptions{Workers: workers, Progress: func(done, total int) {
+				calls++
+				lastDone = done
+				if total != len(pair.patterns)*(len(pair.patterns)-1)/2 {
+					t.Error("expected", len(pair.patterns)*(len(pair.patterns)-1)/2, "pairs in total, got", total)
+				}
+			}}
+			v, err := EditDistanceMatrixParallel(context.Background(), pair.patterns, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(v, expected) {
+				t.Error("For", pair.patterns, "with", workers, "workers expected", expected, "got", v)
+			}
+			if calls != lastDone || calls != len(pair.patterns)*(len(pair.patterns)-1)/2 {
+				t.Error("For", pair.patterns, "expected one progress call per pair, got", calls)
+			}
+		}
+	}
+}
+
+func TestEditDistanceMatrixParallelCancel(t *testing.T) {
+	patterns := []string{"ACGT", "AGT", "ACT", "GGT", "TTT", "ACGGT"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := EditDistanceMatrixParallel(ctx, patterns, MatrixOptions{}); err != context.Canceled {
+		t.Error("expected context.Canceled for a canceled context, got", err)
+	}
+
+	// cancel from the progress callback after the first pair
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	opts := MatrixOptions{Workers: 2, Progress: func(done, total int) { cancel() }}
+	if v, err := EditDistanceMatrixParallel(ctx, patterns, opts); err != context.Canceled || v != nil {
+		t.Error("expected context.Canceled and no matrix, got", v, err)
+	}
+}
+
+func TestModelDistanceMatrixParallel(t *testing.T) {
+	patterns := []string{"ACGTACGTTA", "ACGAACGTTA", "ACGTTCGTAA", "ACCTACGTTA"}
+	expected, err := ModelDistanceMatrix(patterns, JukesCantorModel, 1, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := ModelDistanceMatrixParallel(context.Background(), patterns, JukesCantorModel, 1, 1, 1, MatrixOptions{Workers: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(v, expected) {
+		t.Error("expected", expected, "got", v)
+	}
+
+	// a pair without a distance is reported as an error instead of crashing a worker
+	patterns = append(patterns, "NNNNNNNNNN")
+	if v, err := ModelDistanceMatrixParallel(context.Background(), patterns, JukesCantorModel, 1, 1, 1, MatrixOptions{Workers: 3}); !errors.Is(err, ErrNoSites) || v != nil {
+		t.Error("expected ErrNoSites and no matrix, got", v, err)
+	}
+}
+
 //ReadMatrixFromFile takes a file name and reads the information in this file to produce
 //a distance matrix and a slice of strings holding the species names.  The first line of the
 //file should contain the number of species.  Each other line contains a species name
@@ -325,190 +394,251 @@ func TestGlobalAlignment(t *testing.T) {
 }
 
 /********************************************
- Hamming Distance Tests
+ Linear-Space Global Alignment Tests
 *********************************************/
 
-type hammingTestPair struct {
-	str1  string
-	str2  string
-	hDist int
+func TestGlobalAlignmentLinearSpace(t *testing.T) {
+	inputs := make([]GlobalAlignmentInput, 0)
+	for _, pair := range globalTests {
+		inputs = append(inputs, pair.input)
+	}
+	for _, pair := range levenshteinDistanceTests {
+		if strings.Contains(pair.str1+pair.str2, "-") {
+			continue
+		}
+		inputs = append(inputs, GlobalAlignmentInput{pair.str1, pair.str2, 1.0, 1.0, 0.5})
+		inputs = append(inputs, GlobalAlignmentInput{pair.str1, pair.str2, 1.0, 3.0, 2.0})
+	}
+
+	for _, in := range inputs {
+		v := GlobalAlignmentLinearSpace(in.str1, in.str2, in.match, in.mismatch, in.gap)
+		if strings.Replace(v[0], "-", "", -1) != in.str1 || strings.Replace(v[1], "-", "", -1) != in.str2 {
+			t.Error("For", in, "alignment", v, "does not spell out the input strings")
+		}
+
+		// score with gaps penalized, which is what the score table optimizes
+		score := computeAffineScore(v, in.match, in.mismatch, in.gap, in.gap)
+		table := GlobalScoreTable(in.str1, in.str2, in.match, in.mismatch, in.gap)
+		expected := table[len(in.str1)][len(in.str2)]
+		if score != expected {
+			t.Error(
+				"For", in,
+				"expected alignment with score", expected,
+				"got", v, "with a score of", score,
+			)
+		}
+	}
 }
 
 /********************************************
- LCS length Tests
+ Banded Global Alignment Tests
 *********************************************/
 
-type lcsLengthTestpair struct {
-	str1      string
-	str2      string
-	lcsLength int
+func TestBandedGlobalScoreTable(t *testing.T) {
+	for _, pair := range globalScoreTests {
+		in := pair.input
+		for _, k := range []int{0, 1, 2, 10} {
+			v := BandedGlobalScoreTable(in.str1, in.str2, in.match, in.mismatch, in.gap, k)
+			for i := range pair.scoreMatrix {
+				for j := range pair.scoreMatrix[i] {
+					// a banded score can never beat the unrestricted score
+					if v.InBand(i, j) && v.At(i, j) > pair.scoreMatrix[i][j] {
+						t.Error("For", in, "and k =", k, "got", v.At(i, j), "at", i, j,
+							"which exceeds", pair.scoreMatrix[i][j])
+					}
+					if k == 10 && v.At(i, j) != pair.scoreMatrix[i][j] {
+						t.Error("For", in, "and k =", k, "expected", pair.scoreMatrix[i][j],
+							"at", i, j, "got", v.At(i, j))
+					}
+				}
+			}
+		}
+	}
 }
 
-var lcsLengthTests = []lcsLengthTestpair{
-	{"ATGCGGCTAGCTTAGCCTAGATCGATCGGCTAGCTAGCTAGCCGAGGCTCTCGATCGATCGCGCTAGG",
-		"ATGCGGCTGGCTTAGCCTAGTTCGATCGCGTTCGTAGCTATAGAGCTAGCTAGATCGATCGCGCTAGG", 59},
-	{"TTGCGGAGCTAGGGATCCGATCGAATATCGATATTCGATCGGGAACACAGATCGAT",
-		"GGTACATCGATTCTAGATTCTATAGCGCGCTTCGATCGATTCGATCGATCGAAAAG", 37},
-	{"TTGCGGAGCTAGGGATCCGATCGAATATCGATATTCGATCGGGAACACAGATCGAT",
-		"TTGCGGAGCTAGGGATCCGATCGAATATCGATATTCGATCGGGAACACAGATCGAT", 56},
-	{"TTGCGGAGCTAGGGATCCGATCGAATATCGATATTCGATCGGGAACACAGATCGAT",
-		"TTGCGGAGCTAGGGATCCGATCGAATATCGATATAGCATCGGGAACACAGATCGAT", 54},
-	{"AGCT",
-		"TCGA", 1},
-	{"AATT",
-		"CCGG", 0},
-	{"AGCT",
-		"TGAC", 2},
-	{"---ATCGATC--ATCGATT-GGACAT",
-		"--ATGATGCATC---ATCGGTAG---GCTTAGCTTTTAG", 20}}
+func TestBandedGlobalAlignment(t *testing.T) {
+	inputs := make([]GlobalAlignmentInput, 0)
+	for _, pair := range globalTests {
+		inputs = append(inputs, pair.input)
+	}
 
-func TestLCSLength(t *testing.T) {
-	for _, pair := range lcsLengthTests {
-		v := LCSLength(pair.str1, pair.str2)
-		if v != pair.lcsLength {
+	// a long indel forces the band to widen several times
+	left := strings.Repeat("ACGTTGCATG", 6)
+	right := strings.Repeat("TTAGCCGATC", 6)
+	insert := strings.Repeat("GGGAAATTTC", 7)
+	inputs = append(inputs, GlobalAlignmentInput{left + insert + right, left + right, 1.0, 1.0, 0.5})
+	inputs = append(inputs, GlobalAlignmentInput{left + right, left + "A" + insert + right, 1.0, 3.0, 1.0})
+
+	for _, in := range inputs {
+		v := BandedGlobalAlignment(in.str1, in.str2, in.match, in.mismatch, in.gap)
+		if strings.Replace(v[0], "-", "", -1) != in.str1 || strings.Replace(v[1], "-", "", -1) != in.str2 {
+			t.Error("For", in, "alignment", v, "does not spell out the input strings")
+		}
+
+		score := computeAffineScore(v, in.match, in.mismatch, in.gap, in.gap)
+		table := GlobalScoreTable(in.str1, in.str2, in.match, in.mismatch, in.gap)
+		expected := table[len(in.str1)][len(in.str2)]
+		if score != expected {
 			t.Error(
-				"For", pair.str1,
-				"and", pair.str2,
-				"expected", strconv.Itoa(pair.lcsLength),
-				"got", strconv.Itoa(v),
+				"For", in,
+				"expected alignment with score", expected,
+				"got", v, "with a score of", score,
 			)
 		}
 	}
 }
 
 /********************************************
- Levenshtein Distance Tests
+ Alignment Mode Tests
 *********************************************/
 
-type levenshteinDistanceTestpair struct {
-	str1    string
-	str2    string
-	levDist int
+type modetestpair struct {
+	input LocalAlignmentInput
+	mode  AlignmentMode
+	sol   Solution
 }
 
-var levenshteinDistanceTests = []levenshteinDistanceTestpair{
-	{"A--T",
-		"A--T", 0},
-	{"ATGCGGCTAGCTTAGCCTAGATCGATCGGCTAGCTAGCTAGCCGAGGCTCTCGATCGATCGCGCTAGG",
-		"ATGCGGCTGGCTTAGCCTAGTTCGATCGCGTTCGTAGCTATAGAGCTAGCTAGATCGATCGCGCTAGG", 13},
-	{"TTGCGGAGCTAGGGATCCGATCGAATATCGATATTCGATCGGGAACACAGATCGAT",
-		"GGTACATCGATTCTAGATTCTATAGCGCGCTTCGATCGATTCGATCGATCGAAAAG", 29},
-	{"TTGCGGAGCTAGGGA---TCCGATCGAATATCGATAT---TCGATCGGGAACAC---AGATCGAT",
-		"TTGCG-GAGCTAGGGATCCGATCGAATATCGATATTCGATCGGGAA---CACAGATCGAT", 13},
-	{"TTGCGGAGCTAGGGATCCGATCGAATATCGATATTCGATCGGGAACACAGATCGAT",
-		"TTGCGGAGCTAGGGATCCGATCGAATATCGATATAGCATCGGGAACACAGATCGAT", 3},
-	{"TTGCGGAG-CTAGGGATCCGATCGAATA---TCGATATTCGATCGGGAACACAGATCGAT",
-		"TT--GCGGAGCTAGGGATCCGATCGAATATCGATATAGCAT----CGGGAAC--ACAGATCGAT", 15}}
+var modeTests = []modetestpair{
+	{LocalAlignmentInput{"GGGGGACGTACGTGGGGG",
+		"ACGTACGT",
+		1.0, 1.0, 1.0},
+		FittingMode,
+		Solution{8.000, 5, 13, 0, 8}},
+
+	{LocalAlignmentInput{"GGGGGACGTTACGTGGGGG",
+		"ACGTACGT",
+		1.0, 2.0, 1.0},
+		FittingMode,
+		Solution{7.000, 5, 14, 0, 8}},
+
+	{LocalAlignmentInput{"TTTTTACGTAC",
+		"ACGTACGGGGG",
+		1.0, 1.0, 1.0},
+		OverlapMode,
+		Solution{6.000, 5, 11, 0, 6}},
+
+	{LocalAlignmentInput{"ACGTACGGG",
+		"TTTACGTAC",
+		1.0, 1.0, 1.0},
+		SemiGlobalMode,
+		Solution{6.000, 0, 6, 3, 9}},
+
+	{LocalAlignmentInput{"ACGTACGGG",
+		"TTTACGTAC",
+		1.0, 1.0, 1.0},
+		GlobalMode,
+		Solution{0.000, 0, 9, 0, 9}}}
+
+func TestModeAlignment(t *testing.T) {
+	for _, pair := range modeTests {
+		in := pair.input
+		optAlignment, start0, end0, start1, end1 := ModeAlignment(in.str1, in.str2, in.match, in.mismatch, in.gap, pair.mode)
+		score := computeAffineScore(optAlignment, in.match, in.mismatch, in.gap, in.gap)
+		v := Solution{score, start0, end0, start1, end1}
+		if !reflect.DeepEqual(v, pair.sol) {
+			t.Error(
+				"For", in, "in", pair.mode, "mode",
+				"expected", pair.sol,
+				"got", optAlignment, "with", v,
+			)
+		}
+	}
+}
 
-func TestEditDistance(t *testing.T) {
-	for _, pair := range levenshteinDistanceTests {
-		v := EditDistance(pair.str1, pair.str2)
-		if v != pair.levDist {
+func TestModeScoreTable(t *testing.T) {
+	// in global mode, nothing is free
+	for _, pair := range globalScoreTests {
+		in := pair.input
+		v := ModeScoreTable(in.str1, in.str2, in.match, in.mismatch, in.gap, GlobalMode)
+		if !reflect.DeepEqual(v, pair.scoreMatrix) {
 			t.Error(
-				"For", pair.str1,
-				"and", pair.str2,
-				"expected", strconv.Itoa(pair.levDist),
-				"got", strconv.Itoa(v),
+				"For", in,
+				"expected scoring matrix", pair.scoreMatrix,
+				"got", v,
 			)
 		}
 	}
 }
 
 /********************************************
- Local Alignment Score Tests
+ CIGAR Tests
 *********************************************/
 
-type LocalAlignmentInput struct {
-	str1     string
-	str2     string
-	match    float64
-	mismatch float64
-	gap      float64
+type cigarTestpair struct {
+	alignment Alignment
+	cigar     string
 }
 
-type localscoretestpair struct {
-	input       LocalAlignmentInput
-	scoreMatrix [][]float64
+var cigarTests = []cigarTestpair{
+	{Alignment{"ACGT", "ACGT"}, "4="},
+	{Alignment{"ACGTTA", "AC--TT"}, "2=2D1=1X"},
+	{Alignment{"A--CGT", "AGGCGA"}, "1=2I2=1X"},
+	{Alignment{"A-CG", "A-TG"}, "1=1X1="},
+	{Alignment{"", ""}, "*"}}
+
+func TestAlignmentCIGAR(t *testing.T) {
+	for _, pair := range cigarTests {
+		v := CIGARString(AlignmentCIGAR(pair.alignment))
+		if v != pair.cigar {
+			t.Error(
+				"For", pair.alignment,
+				"expected", pair.cigar,
+				"got", v,
+			)
+		}
+	}
 }
 
-var localScoreTests = []localscoretestpair{
-	{LocalAlignmentInput{"A",
-		"A",
-		1.0, 0.5, 1.0},
-		[][]float64{[]float64{0, 0}, []float64{0, 1}}},
-
-	{LocalAlignmentInput{"GAAC",
-		"CAAG",
-		1.0, 1.0, 0.5},
-		[][]float64{[]float64{0, 0, 0, 0, 0},
-			[]float64{0, 0, 0, 0, 1},
-			[]float64{0, 0, 1, 1, 0.5},
-			[]float64{0, 0, 1, 2, 1.5},
-			[]float64{0, 1, 0.5, 1.5, 1}}},
-
-	{LocalAlignmentInput{"GAAC",
-		"CAAG",
-		1.0, 0.5, 1.0},
-		[][]float64{[]float64{0, 0, 0, 0, 0},
-			[]float64{0, 0, 0, 0, 1},
-			[]float64{0, 0, 1, 1, 0},
-			[]float64{0, 0, 1, 2, 1},
-			[]float64{0, 1, 0, 1, 1.5}}},
-
-	{LocalAlignmentInput{"TACG",
-		"CACG",
-		1.0, 1.0, 0.5},
-		[][]float64{[]float64{0, 0, 0, 0, 0},
-			[]float64{0, 0, 0, 0, 0},
-			[]float64{0, 0, 1, 0.5, 0},
-			[]float64{0, 1, 0.5, 2, 1.5},
-			[]float64{0, 0.5, 0, 1.5, 3}}},
-
-	{LocalAlignmentInput{"TACGG",
-		"CACG",
-		1.0, 0.5, 1.0},
-		[][]float64{[]float64{0, 0, 0, 0, 0},
-			[]float64{0, 0, 0, 0, 0},
-			[]float64{0, 0, 1, 0, 0},
-			[]float64{0, 1, 0, 2, 1},
-			[]float64{0, 0, 0.5, 1, 3},
-			[]float64{0, 0, 0, 0, 2}}},
-
-	{LocalAlignmentInput{"ATCGATCGT",
-		"ATCGGCTAC",
-		1.0, 1.0, 0.5},
-		[][]float64{[]float64{0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
-			[]float64{0, 1, 0.5, 0, 0, 0, 0, 0, 1, 0.5},
-			[]float64{0, 0.5, 2, 1.5, 1, 0.5, 0, 1, 0.5, 0},
-			[]float64{0, 0, 1.5, 3, 2.5, 2, 1.5, 1, 0.5, 1.5},
-			[]float64{0, 0, 1, 2.5, 4, 3.5, 3, 2.5, 2, 1.5},
-			[]float64{0, 1, 0.5, 2, 3.5, 3, 2.5, 2, 3.5, 3},
-			[]float64{0, 0.5, 2, 1.5, 3, 2.5, 2, 3.5, 3, 2.5},
-			[]float64{0, 0, 1.5, 3, 2.5, 2, 3.5, 3, 2.5, 4},
-			[]float64{0, 0, 1, 2.5, 4, 3.5, 3, 2.5, 2, 3.5},
-			[]float64{0, 0, 1, 2, 3.5, 3, 2.5, 4, 3.5, 3}}},
+/********************************************
+ Variant Calling Tests
+*********************************************/
 
-	{LocalAlignmentInput{"ATCGATCGT",
-		"AAC",
-		1.0, 1.0, 0.5},
-		[][]float64{[]float64{0, 0, 0, 0},
-			[]float64{0, 1, 1, 0.5},
-			[]float64{0, 0.5, 0.5, 0},
-			[]float64{0, 0, 0, 1.5},
-			[]float64{0, 0, 0, 1},
-			[]float64{0, 1, 1, 0.5},
-			[]float64{0, 0.5, 0.5, 0},
-			[]float64{0, 0, 0, 1.5},
-			[]float64{0, 0, 0, 1},
-			[]float64{0, 0, 0, 0.5}}}}
+type variantTestpair struct {
+	alignment Alignment
+	variants  []Variant
+}
 
-func TestLocalScoreTable(t *testing.T) {
-	for _, pair := range localScoreTests {
-		v := LocalScoreTable(pair.input.str1, pair.input.str2, pair.input.match, pair.input.mismatch, pair.input.gap)
-		if !reflect.DeepEqual(v, pair.scoreMatrix) {
+var variantTests = []variantTestpair{
+	{Alignment{"ACGT", "ACGT"}, []Variant{}},
+	{Alignment{"ACGT", "AGGA"}, []Variant{
+		{Pos: 2, Ref: "C", Alt: "G", Type: SNP, Column: 1},
+		{Pos: 4, Ref: "T", Alt: "A", Type: SNP, Column: 3}}},
+	{Alignment{"ACGTTA", "AC---A"}, []Variant{
+		{Pos: 2, Ref: "CGTT", Alt: "C", Type: Deletion, Column: 2}}},
+	{Alignment{"AC--GT", "ACTTGT"}, []Variant{
+		{Pos: 2, Ref: "C", Alt: "CTT", Type: Insertion, Column: 2}}},
+	{Alignment{"--ACGT", "GGACGT"}, []Variant{
+		{Pos: 1, Ref: "A", Alt: "GGA", Type: Insertion, Column: 0}}},
+	{Alignment{"ACG--T", "A--TTT"}, []Variant{
+		{Pos: 1, Ref: "ACG", Alt: "ATT", Type: Complex, Column: 1}}},
+	{Alignment{"AC-GT", "A-CGT"}, []Variant{}},
+	// a mismatch right before a deletion is the deletion's anchor, so they form one event
+	{Alignment{"ACGTTA", "AT--TA"}, []Variant{
+		{Pos: 2, Ref: "CGT", Alt: "T", Type: Complex, Column: 1}}},
+	{Alignment{"ACGTA", "AGGC-"}, []Variant{
+		{Pos: 2, Ref: "C", Alt: "G", Type: SNP, Column: 1},
+		{Pos: 4, Ref: "TA", Alt: "C", Type: Complex, Column: 3}}},
+	{Alignment{"AC--GT", "AGTTGT"}, []Variant{
+		{Pos: 2, Ref: "C", Alt: "GTT", Type: Complex, Column: 1}}},
+	// at the start of the reference the anchor follows the event
+	{Alignment{"--ACGT", "GGTCGT"}, []Variant{
+		{Pos: 1, Ref: "A", Alt: "GGT", Type: Complex, Column: 0}}},
+	// an event at the start of the reference and an indel after its anchor share the anchor,
+	// whether or not it is substituted
+	{Alignment{"-AGT", "TC-T"}, []Variant{
+		{Pos: 1, Ref: "AG", Alt: "TC", Type: Complex, Column: 0}}},
+	{Alignment{"-AGT", "TA-T"}, []Variant{
+		{Pos: 1, Ref: "AG", Alt: "TA", Type: Complex, Column: 0}}},
+	{Alignment{"AGCT", "-G-T"}, []Variant{
+		{Pos: 1, Ref: "AGC", Alt: "G", Type: Complex, Column: 0}}}}
+
+func TestCallVariants(t *testing.T) {
+	for _, pair := range variantTests {
+		v := CallVariants(pair.alignment)
+		if !reflect.DeepEqual(v, pair.variants) {
 			t.Error(
-				"For", pair.input,
-				"expected scoring matrix", pair.scoreMatrix,
+				"For", pair.alignment,
+				"expected", pair.variants,
 				"got", v,
 			)
 		}
@@ -516,37 +646,1077 @@ func TestLocalScoreTable(t *testing.T) {
 }
 
 /********************************************
- Local Alignment Tests
+ Genome Annotation Tests
 *********************************************/
 
-type Solution struct {
-	score  float64
-	start0 int
-	end0   int
-	start1 int
-	end1   int
-}
+//readGenome takes the name of a single-record FASTA file and returns its sequence.
+func readGenome(t testing.TB, filename string) string {
+	file, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
 
-type testpair struct {
-	input LocalAlignmentInput
-	sol   Solution
+	var b strings.Builder
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) > 0 && line[0] != '>' {
+			b.WriteString(line)
+		}
+	}
+	return b.String()
 }
 
-var tests = []testpair{
-	{LocalAlignmentInput{"GAAC",
-		"CAAG",
-		1.0, 1.0, 0.5},
-		Solution{2.000, 1, 3, 1, 3}},
+func TestReadAnnotationFile(t *testing.T) {
+	features, err := ReadAnnotationFile("../Data/Coronaviruses/SARS-CoV-2_annotation.gff3")
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	{LocalAlignmentInput{"TAAC",
-		"TAAG",
-		1.0, 1.0, 0.5},
-		Solution{3.000, 0, 3, 0, 3}},
+	genome := readGenome(t, "../Data/Coronaviruses/SARS-CoV-2_genome.fasta")
+	numCDS := 0
+	for _, f := range features {
+		if f.Type != "CDS" {
+			continue
+		}
+		numCDS++
+		// every CDS should start with ATG and have a whole number of codons
+		if genome[f.Start()-1:f.Start()+2] != "ATG" || f.Length()%3 != 0 {
+			t.Error("CDS", f.Name, "at", f.Segments, "is not a whole open reading frame")
+		}
+		if f.Name == "ORF1ab" && len(f.Segments) != 2 {
+			t.Error("expected ORF1ab to have two segments, got", f.Segments)
+		}
+	}
+	if numCDS != 12 {
+		t.Error("expected 12 CDS features, got", numCDS)
+	}
+}
 
-	{LocalAlignmentInput{"A",
-		"A",
-		1.0, 0.5, 1.0},
-		Solution{1.000, 0, 1, 0, 1}},
+func TestParseGFF3Errors(t *testing.T) {
+	tests := []struct {
+		line    string
+		message string
+	}{
+		{"seq\tsrc\tCDS\t1\t9\t.\t+\t0", "line 2: expected 9 tab-separated columns, found 8"},
+		{"seq\tsrc\tCDS\tone\t9\t.\t+\t0\tID=a", `line 2: bad start "one"`},
+		{"seq\tsrc\tCDS\t1\t9\t.\t\t0\tID=a", `line 2: bad strand ""`},
+		{"seq\tsrc\tCDS\t1\t9\t.\t+-\t0\tID=a", `line 2: bad strand "+-"`},
+		{"seq\tsrc\tCDS\t1\t9\t.\tx\t0\tID=a", `line 2: bad strand "x"`}}
+	for _, test := range tests {
+		_, err := ParseGFF3(strings.NewReader("##gff-version 3\n" + test.line + "\n"))
+		if err == nil || err.Error() != test.message {
+			t.Error("For", test.line, "expected error", test.message, "got", err)
+		}
+	}
+}
+
+func TestParseGenBankFeatures(t *testing.T) {
+	text := `LOCUS       TEST                      60 bp    RNA     linear   VRL
+VERSION     TEST.1
+FEATURES             Location/Qualifiers
+     gene            10..30
+                     /gene="alpha"
+     CDS             join(10..20,
+                     25..30)
+                     /gene="alpha"
+                     /product="a made up
+                     protein"
+     CDS             complement(<40..>55)
+                     /locus_tag="beta"
+ORIGIN
+`
+	features, err := ParseGenBankFeatures(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Feature{
+		{SeqID: "TEST.1", Type: "gene", Name: "alpha", Gene: "alpha", Strand: '+',
+			Segments: []FeatureSegment{{10, 30}}},
+		{SeqID: "TEST.1", Type: "CDS", Name: "alpha", Gene: "alpha", Product: "a made up protein", Strand: '+',
+			Segments: []FeatureSegment{{10, 20}, {25, 30}}},
+		{SeqID: "TEST.1", Type: "CDS", ID: "beta", Name: "beta", Strand: '-',
+			Segments: []FeatureSegment{{40, 55}}}}
+	if !reflect.DeepEqual(features, expected) {
+		t.Error("expected", expected, "got", features)
+	}
+}
+
+func TestAnnotateVariants(t *testing.T) {
+	features, err := ReadAnnotationFile("../Data/Coronaviruses/SARS-CoV-2_annotation.gff3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	genome := readGenome(t, "../Data/Coronaviruses/SARS-CoV-2_genome.fasta")
+
+	// the spike D614G substitution (A23403G), a synonymous change in the next codon,
+	// an ORF8 deletion of a whole codon, and a change in the 3' UTR
+	variant := []byte(genome)
+	variant[23403-1] = 'G'
+	variant[23407-1] = 'C'
+	copy(variant[27900:27903], "---")
+	variant[29800-1] = 'A'
+
+	// the annotated genome is the bottom row
+	a := Alignment{string(variant), genome}
+	v := AnnotateVariants(a, 1, features)
+
+	expected := []struct {
+		pos    int
+		gene   string
+		change string
+		effect VariantEffect
+	}{
+		{23403, "S", "D614G", Nonsynonymous},
+		{23407, "S", "V615V", Synonymous},
+		{27900, "ORF8", "", InFrameIndel},
+		{29800, "", "", NonCoding}}
+
+	if len(v) != len(expected) {
+		t.Fatal("expected", len(expected), "annotated variants, got", v)
+	}
+	for i := range expected {
+		if v[i].Pos != expected[i].pos || v[i].Gene != expected[i].gene ||
+			v[i].AminoAcidChange != expected[i].change || v[i].Effect != expected[i].effect {
+			t.Error("expected", expected[i], "got", v[i])
+		}
+	}
+}
+
+func TestAnnotateSubstitutionNextToIndel(t *testing.T) {
+	features, err := ReadAnnotationFile("../Data/Coronaviruses/SARS-CoV-2_annotation.gff3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	genome := readGenome(t, "../Data/Coronaviruses/SARS-CoV-2_genome.fasta")
+
+	// spike codon 614 is GAT at 23402-23404; change its first base and delete its last
+	deletion := []byte(genome)
+	deletion[23402-1] = 'C'
+	copy(deletion[23404-1:23407-1], "---")
+	// or change its first base and insert three bases after its second
+	insertion := genome[:23402-1] + "C" + genome[23402:23403] + "TTT" + genome[23403:]
+	reference := genome[:23403] + "---" + genome[23403:]
+
+	tests := []struct {
+		a        Alignment
+		altCodon string
+	}{
+		{Alignment{string(deletion), genome}, "CA-"},
+		{Alignment{insertion, reference}, "CAT"},
+	}
+	for _, test := range tests {
+		v := AnnotateVariants(test.a, 1, features)
+		if len(v) != 2 {
+			t.Fatal("expected a substitution and an indel, got", v)
+		}
+		if v[0].Pos != 23402 || v[0].Effect != IndelInCodon || v[0].AltCodon != test.altCodon || v[0].AminoAcidChange != "" {
+			t.Error("expected a substitution in a codon with an indel, with no amino acid change, got", v[0])
+		}
+		if v[1].Effect != InFrameIndel {
+			t.Error("expected an in-frame indel, got", v[1])
+		}
+	}
+}
+
+/********************************************
+ Translation Tests
+*********************************************/
+
+func TestGeneticCode(t *testing.T) {
+	if aa := StandardCode.TranslateCodon("TGA"); aa != '*' {
+		t.Error("expected TGA to be a stop in the standard code, got", string(aa))
+	}
+	code, ok := GeneticCodeByID(2)
+	if !ok || code.Name != "Vertebrate Mitochondrial" {
+		t.Fatal("expected to find the vertebrate mitochondrial code, got", code, ok)
+	}
+	if code.TranslateCodon("TGA") != 'W' || code.TranslateCodon("AGA") != '*' || !code.IsStart("ATA") {
+		t.Error("wrong codons in the vertebrate mitochondrial code")
+	}
+	if aa := StandardCode.TranslateCodon("AUG"); aa != 'M' {
+		t.Error("expected RNA codon AUG to give M, got", string(aa))
+	}
+	if aa := StandardCode.TranslateCodon("AN-"); aa != 'X' {
+		t.Error("expected ambiguous codon to give X, got", string(aa))
+	}
+	if protein := StandardCode.TranslateToStop("ATGAAATAGGGG"); protein != "MK" {
+		t.Error("expected MK, got", protein)
+	}
+}
+
+func TestSixFrameTranslations(t *testing.T) {
+	v := SixFrameTranslations("ATGAAATAG", StandardCode)
+	expected := [6]string{"MK*", "*N", "EI", "LFH", "YF", "IS"}
+	if v != expected {
+		t.Error("expected", expected, "got", v)
+	}
+}
+
+func TestFindORFs(t *testing.T) {
+	// one ORF in frame +3, and one in frame -1 that ends at the reverse strand's TGA
+	dna := "CCATGAAATAGCC" + ReverseComplement("ATGCCCTGA")
+	v := FindORFs(dna, StandardCode, 2)
+	expected := []ORF{
+		{Frame: 3, Start: 2, End: 11, Protein: "MK"},
+		{Frame: -1, Start: 13, End: 22, Protein: "MP"}}
+	if !reflect.DeepEqual(v, expected) {
+		t.Error("expected", expected, "got", v)
+	}
+}
+
+func TestCDSAlignment(t *testing.T) {
+	// cds1 is missing the lysine codon AAA
+	v := CDSAlignment("ATGGCTAAATGGTAA", "ATGGCTTGGTAA", StandardCode, BLOSUM62, 5)
+	expected := Alignment{"ATGGCTAAATGGTAA", "ATGGCT---TGGTAA"}
+	if v != expected {
+		t.Error("expected", expected, "got", v)
+	}
+
+	// ORFs found in a longer sequence can be aligned directly
+	orfs := FindORFs("CCATGGCTAAATGGTAACC", StandardCode, 3)
+	if len(orfs) == 0 {
+		t.Fatal("expected an ORF")
+	}
+	orf := "CCATGGCTAAATGGTAACC"[orfs[0].Start:orfs[0].End]
+	if v := CDSAlignment(orf, "ATGGCTTGGTAA", StandardCode, BLOSUM62, 5); v != expected {
+		t.Error("expected", expected, "got", v)
+	}
+
+	// partial codons and internal stops aren't coding sequences
+	for _, cds := range []string{"ATGGCTTGGTAAAC", "ATGTAAGCTTGG", ""} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected a panic for", cds)
+				}
+			}()
+			CDSAlignment(cds, "ATGGCTTGGTAA", StandardCode, BLOSUM62, 5)
+		}()
+	}
+}
+
+/********************************************
+ Multiple Alignment Tests
+*********************************************/
+
+func TestAlignProfiles(t *testing.T) {
+	// profiles of one row each should align like GlobalAlignment
+	for _, pair := range globalTests {
+		in := pair.input
+		v := AlignProfiles(MultipleAlignment{in.str1}, MultipleAlignment{in.str2}, in.match, in.mismatch, in.gap)
+		table := GlobalScoreTable(in.str1, in.str2, in.match, in.mismatch, in.gap)
+		expected := table[len(in.str1)][len(in.str2)]
+		if score := SumOfPairsScore(v, in.match, in.mismatch, in.gap); score != expected {
+			t.Error("For", in, "expected profile alignment with score", expected, "got", v, "with score", score)
+		}
+	}
+
+	// a gap column is inserted into both rows of the second profile
+	v := AlignProfiles(MultipleAlignment{"ACGT", "ACGT"}, MultipleAlignment{"ACT", "A-T"}, 1, 1, 1)
+	expected := MultipleAlignment{"ACGT", "ACGT", "AC-T", "A--T"}
+	if !reflect.DeepEqual(v, expected) {
+		t.Error("expected", expected, "got", v)
+	}
+}
+
+func TestProgressiveAlignment(t *testing.T) {
+	patterns := []string{"GATTACA", "GATCA", "GATTTACA", "GATTACA"}
+	v := ProgressiveAlignment(patterns, 1, 1, 1)
+	expected := MultipleAlignment{"GA-TTACA", "GA-T--CA", "GATTTACA", "GA-TTACA"}
+	if !reflect.DeepEqual(v, expected) {
+		t.Error("expected", expected, "got", v)
+	}
+
+	// hemoglobins: every row should spell out its input and rows should have equal lengths
+	files := []string{"Homo_sapiens", "Gorilla_gorilla", "Bos_taurus", "Danio_rerio"}
+	proteins := make([]string, len(files))
+	for i, name := range files {
+		proteins[i] = readGenome(t, "../Data/Hemoglobin/"+name+"_hemoglobin.fasta")
+	}
+	ma := ProgressiveAlignmentWithMatrix(proteins, BLOSUM62, 5)
+	if ma.NumRows() != len(proteins) {
+		t.Fatal("expected", len(proteins), "rows, got", ma.NumRows())
+	}
+	for i := range ma {
+		if len(ma[i]) != ma.NumColumns() || strings.Replace(ma[i], "-", "", -1) != proteins[i] {
+			t.Error("row", i, "of", ma, "does not spell out", proteins[i])
+		}
+	}
+}
+
+/********************************************
+ Tree Tests
+*********************************************/
+
+func TestUPGMA(t *testing.T) {
+	mtx := DistanceMatrix{
+		{0, 13, 21, 20},
+		{13, 0, 12, 13},
+		{21, 12, 0, 13},
+		{20, 13, 13, 0}}
+	root := UPGMA(mtx, []string{"i", "j", "k", "l"})
+	expected := "(i:9,((j:6,k:6):0.5,l:6.5):2.5);"
+	if newick := root.Newick(); newick != expected {
+		t.Error("expected", expected, "got", newick)
+	}
+}
+
+func TestNeighborJoining(t *testing.T) {
+	// an additive matrix, which neighbor-joining should reconstruct exactly
+	mtx := DistanceMatrix{
+		{0, 13, 21, 22},
+		{13, 0, 12, 13},
+		{21, 12, 0, 13},
+		{22, 13, 13, 0}}
+	root := NeighborJoining(mtx, []string{"i", "j", "k", "l"})
+	expected := "(k:6,l:7,(i:11,j:2):4);"
+	if newick := root.Newick(); newick != expected {
+		t.Error("expected", expected, "got", newick)
+	}
+
+	if newick := NeighborJoining(DistanceMatrix{{0, 3}, {3, 0}}, []string{"a", "b"}).Newick(); newick != "(a:1.5,b:1.5);" {
+		t.Error("expected (a:1.5,b:1.5); got", newick)
+	}
+	if newick := UPGMA(DistanceMatrix{{0}}, []string{"Homo sapiens"}).Newick(); newick != "'Homo sapiens';" {
+		t.Error("expected a quoted label, got", newick)
+	}
+}
+
+func TestReadDistanceMatrix(t *testing.T) {
+	mtx, labels, err := ReadDistanceMatrix("Tests/matrix4.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedMtx, expectedLabels := ReadMatrixFromFile("Tests/matrix4.txt")
+	if !reflect.DeepEqual(mtx, expectedMtx) || !reflect.DeepEqual(labels, expectedLabels) {
+		t.Error("expected", expectedMtx, expectedLabels, "got", mtx, labels)
+	}
+	if _, _, err := ReadDistanceMatrix("Tests/no_such_matrix.txt"); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+
+	// saturated distances can't be used to build a tree
+	filename := t.TempDir() + "/saturated.txt"
+	if err := os.WriteFile(filename, []byte("2\na\t0\t+Inf\nb\t+Inf\t0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ReadDistanceMatrix(filename); err == nil {
+		t.Error("expected an error for an infinite distance")
+	}
+}
+
+/********************************************
+ Evolutionary Distance Tests
+*********************************************/
+
+func TestNucleotideDistances(t *testing.T) {
+	// four transitions and two transversions, with every base a quarter of both rows
+	a := Alignment{"AACCGGTTAACCGGTT", "GACTAGCTCAACGGTT"}
+	c := NucleotideSubstitutions(a)
+	expected := SubstitutionCounts{Sites: 16, PurineTransitions: 2, PyrimidineTransitions: 2,
+		Transversions: 2, BaseCounts: [4]int{8, 8, 8, 8}}
+	if c != expected {
+		t.Error("expected", expected, "got", c)
+	}
+
+	if d, err := PDistance(a); err != nil || d != 6.0/16.0 {
+		t.Error("expected p-distance 0.375, got", d, err)
+	}
+	if d, err := JukesCantorDistance(a); err != nil || math.Abs(d-(-0.75*math.Log(1-4.0/3.0*0.375))) > 1e-12 {
+		t.Error("expected JC69 distance", -0.75*math.Log(1-4.0/3.0*0.375), "got", d, err)
+	}
+	k80 := -0.5*math.Log(1-2*0.25-0.125) - 0.25*math.Log(1-2*0.125)
+	if d, err := Kimura2PDistance(a); err != nil || math.Abs(d-k80) > 1e-12 {
+		t.Error("expected K80 distance", k80, "got", d, err)
+	}
+	// with equal base frequencies Tamura-Nei reduces to Kimura 2P
+	if d, err := TamuraNeiDistance(a); err != nil || math.Abs(d-k80) > 1e-12 {
+		t.Error("expected TN93 distance", k80, "got", d, err)
+	}
+
+	// gaps and ambiguous symbols are skipped
+	if d, err := PDistance(Alignment{"AC-GT", "ACTGA"}); err != nil || d != 0.25 {
+		t.Error("expected p-distance 0.25, got", d, err)
+	}
+	if c := NucleotideSubstitutions(Alignment{"ACNGT", "acggu"}); c.Sites != 4 || c.Differences() != 0 {
+		t.Error("expected 4 identical sites, got", c)
+	}
+}
+
+func TestDistanceErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		distance func(Alignment) (float64, error)
+		a        Alignment
+		err      error
+	}{
+		{"p-distance with only gapped sites", PDistance, Alignment{"AC--", "--GT"}, ErrNoSites},
+		{"JC69 with only ambiguous sites", JukesCantorDistance, Alignment{"NNRY", "ACGT"}, ErrNoSites},
+		{"K80 with only gapped sites", Kimura2PDistance, Alignment{"A-", "-C"}, ErrNoSites},
+		{"TN93 with only gapped sites", TamuraNeiDistance, Alignment{"A-", "-C"}, ErrNoSites},
+		{"TN93 without G", TamuraNeiDistance, Alignment{"ACTA", "ACTT"}, ErrMissingNucleotides},
+		{"saturated JC69", JukesCantorDistance, Alignment{"ACGT", "CATG"}, ErrSaturated},
+		{"saturated K80", Kimura2PDistance, Alignment{"AACC", "CCAA"}, ErrSaturated},
+		{"saturated Poisson", PoissonDistance, Alignment{"MKV", "LRA"}, ErrSaturated},
+		{"Poisson with only gapped sites", PoissonDistance, Alignment{"M-", "-K"}, ErrNoSites},
+	}
+	for _, test := range tests {
+		if d, err := test.distance(test.a); !errors.Is(err, test.err) {
+			t.Errorf("%s: expected error %v, got %v and distance %v", test.name, test.err, err, d)
+		}
+	}
+}
+
+func TestProteinDistances(t *testing.T) {
+	a := Alignment{"MKV", "MKL"}
+	if d, err := PoissonDistance(a); err != nil || math.Abs(d-(-math.Log(2.0/3.0))) > 1e-12 {
+		t.Error("expected Poisson distance", -math.Log(2.0/3.0), "got", d, err)
+	}
+	expected := -math.Log(1 - 1.0/3.0 - 0.2/9.0)
+	if d, err := KimuraProteinDistance(a); err != nil || math.Abs(d-expected) > 1e-12 {
+		t.Error("expected Kimura distance", expected, "got", d, err)
+	}
+}
+
+func TestModelDistanceMatrix(t *testing.T) {
+	mtx, err := ModelDistanceMatrix([]string{"ACGTACGT", "ACGTACGT", "ACGAACGT"}, PDistanceModel, 1, 1, 1)
+	expected := DistanceMatrix{{0, 0, 0.125}, {0, 0, 0.125}, {0.125, 0.125, 0}}
+	if err != nil || !reflect.DeepEqual(mtx, expected) {
+		t.Error("expected", expected, "got", mtx, err)
+	}
+
+	ma := MultipleAlignment{"MKV-", "MKLA", "M-LA"}
+	mtx, err = MultipleAlignmentDistanceMatrix(ma, PDistanceModel)
+	expected = DistanceMatrix{{0, 1.0 / 3.0, 1.0 / 2.0}, {1.0 / 3.0, 0, 0}, {1.0 / 2.0, 0, 0}}
+	if err != nil || !reflect.DeepEqual(mtx, expected) {
+		t.Error("expected", expected, "got", mtx, err)
+	}
+
+	// the error names the pair that couldn't be estimated
+	ma = MultipleAlignment{"MKV", "MKL", "LRA"}
+	if _, err := MultipleAlignmentDistanceMatrix(ma, PoissonModel); !errors.Is(err, ErrSaturated) || !strings.Contains(err.Error(), "sequences 1 and 3") {
+		t.Error("expected ErrSaturated for sequences 1 and 3, got", err)
+	}
+}
+
+/********************************************
+ Edit Script Tests
+*********************************************/
+
+func TestEditScript(t *testing.T) {
+	for _, pair := range levenshteinDistanceTests {
+		script := EditScript(pair.str1, pair.str2)
+		if len(script) != pair.levDist {
+			t.Error("For", pair.str1, "and", pair.str2, "expected", pair.levDist, "edits, got", script)
+		}
+		v, err := ApplyEditScript(pair.str1, script)
+		if err != nil || v != pair.str2 {
+			t.Error("For", pair.str1, "and", pair.str2, "applying", script, "gave", v, err)
+		}
+	}
+
+	script := EditScript("ACGTTA", "AGTTCA")
+	expected := []EditOperation{
+		{EditDeletion, 1, 1, 'C', '-'},
+		{EditInsertion, 5, 4, '-', 'C'}}
+	if !reflect.DeepEqual(script, expected) {
+		t.Error("expected", expected, "got", script)
+	}
+
+	if script := EditScript("", "AC"); len(script) != 2 || script[1] != (EditOperation{EditInsertion, 0, 1, '-', 'C'}) {
+		t.Error("expected two insertions, got", script)
+	}
+}
+
+func TestAlignmentEditScript(t *testing.T) {
+	str1, str2 := "GATTACAGATTACA", "GCATGCTGATCA"
+	script := AlignmentEditScript(GlobalAlignmentLinearSpace(str1, str2, 0, 1, 1))
+	if len(script) != EditDistance(str1, str2) {
+		t.Error("expected", EditDistance(str1, str2), "edits, got", script)
+	}
+	if v, err := ApplyEditScript(str1, script); err != nil || v != str2 {
+		t.Error("applying", script, "gave", v, err)
+	}
+}
+
+func TestApplyEditScript(t *testing.T) {
+	if _, err := ApplyEditScript("ACGT", []EditOperation{{EditSubstitution, 1, 1, 'G', 'T'}}); err == nil {
+		t.Error("expected an error for a substitution of the wrong symbol")
+	}
+	if _, err := ApplyEditScript("ACGT", []EditOperation{{EditDeletion, 2, 2, 'G', '-'}, {EditDeletion, 0, 0, 'A', '-'}}); err == nil {
+		t.Error("expected an error for edits out of order")
+	}
+}
+
+func TestEditDiff(t *testing.T) {
+	str1 := "GATTACA"
+	v := EditDiff(str1, EditScript(str1, "GCATGCT"))
+	expected := "  1 G\n- 2-3 AT\n+ 2-3 CA\n  4 T\n- 5 A\n+ 5 G\n  6 C\n- 7 A\n+ 7 T\n"
+	if v != expected {
+		t.Error("expected\n"+expected, "got\n"+v)
+	}
+
+	str1 = "ACGTTA"
+	v = EditDiff(str1, EditScript(str1, "AGTTCA"))
+	expected = "  1 A\n- 2 C\n  3-5 GTT\n+ 5 C\n  6 A\n"
+	if v != expected {
+		t.Error("expected\n"+expected, "got\n"+v)
+	}
+
+	long := strings.Repeat("ACGT", 10)
+	if v := EditDiff(long+"A", EditScript(long+"A", long+"C")); v != "  1-40 (40 unchanged symbols)\n- 41 A\n+ 41 C\n" {
+		t.Error("expected a summarized run of unchanged symbols, got\n" + v)
+	}
+}
+
+/********************************************
+ Damerau-Levenshtein and Weighted Distance Tests
+*********************************************/
+
+type damerauTestpair struct {
+	str1   string
+	str2   string
+	osa    int
+	damLev int
+}
+
+var damerauTests = []damerauTestpair{
+	{"ACGT", "CAGT", 1, 1},
+	{"CA", "ABC", 3, 2},
+	{"GATTACA", "GATTACA", 0, 0},
+	{"", "ACG", 3, 3},
+	{"TCGA", "", 4, 4},
+	{"ABCDEF", "BADCFE", 3, 3},
+	{"ACGTACGT", "CATGACGT", 2, 2},
+	{"ACGT", "TGCA", 3, 3}}
+
+func TestDamerauLevenshteinDistance(t *testing.T) {
+	for _, pair := range damerauTests {
+		if v := OSADistance(pair.str1, pair.str2); v != pair.osa {
+			t.Error("For", pair.str1, "and", pair.str2, "expected OSA distance", pair.osa, "got", v)
+		}
+		if v := DamerauLevenshteinDistance(pair.str1, pair.str2); v != pair.damLev {
+			t.Error("For", pair.str1, "and", pair.str2, "expected Damerau-Levenshtein distance", pair.damLev, "got", v)
+		}
+	}
+
+	// without adjacent swaps, both agree with the edit distance
+	for _, pair := range levenshteinDistanceTests {
+		if v := OSADistance(pair.str1, pair.str2); v > pair.levDist {
+			t.Error("For", pair.str1, "and", pair.str2, "OSA distance", v, "exceeds edit distance", pair.levDist)
+		}
+		if v, osa := DamerauLevenshteinDistance(pair.str1, pair.str2), OSADistance(pair.str1, pair.str2); v > osa {
+			t.Error("For", pair.str1, "and", pair.str2, "Damerau-Levenshtein distance", v, "exceeds OSA distance", osa)
+		}
+	}
+
+	patterns := []string{"CA", "AC", "ABC"}
+	if v, expected := OSADistanceMatrix(patterns), [][]int{{0, 1, 3}, {1, 0, 1}, {3, 1, 0}}; !reflect.DeepEqual(v, expected) {
+		t.Error("expected", expected, "got", v)
+	}
+	if v, expected := DamerauLevenshteinDistanceMatrix(patterns), [][]int{{0, 1, 2}, {1, 0, 1}, {2, 1, 0}}; !reflect.DeepEqual(v, expected) {
+		t.Error("expected", expected, "got", v)
+	}
+}
+
+func TestWeightedEditDistance(t *testing.T) {
+	for _, pair := range levenshteinDistanceTests {
+		if v := WeightedEditDistance(pair.str1, pair.str2, UnitEditCosts()); v != float64(pair.levDist) {
+			t.Error("For", pair.str1, "and", pair.str2, "expected", pair.levDist, "got", v)
+		}
+	}
+
+	costs := TransitionTransversionCosts(0.5, 1, 2)
+	tests := []struct {
+		str1, str2 string
+		expected   float64
+	}{
+		{"ACGT", "GCGT", 0.5},
+		{"ACGT", "CCGT", 1},
+		{"acgt", "ACGC", 0.5},
+		{"ACGT", "ACG", 2},
+		{"ACGT", "GTGC", 1.5}}
+	for _, test := range tests {
+		if v := WeightedEditDistance(test.str1, test.str2, costs); v != test.expected {
+			t.Error("For", test.str1, "and", test.str2, "expected", test.expected, "got", v)
+		}
+	}
+
+	// deleting an A is cheap, so the matrix need not be symmetric
+	costs = UnitEditCosts()
+	costs.Deletion = map[byte]float64{'A': 0.25}
+	v := WeightedEditDistanceMatrix([]string{"AC", "C"}, costs)
+	if expected := (DistanceMatrix{{0, 0.25}, {1, 0}}); !reflect.DeepEqual(v, expected) {
+		t.Error("expected", expected, "got", v)
+	}
+}
+
+/********************************************
+ MinHash Tests
+*********************************************/
+
+func TestMinHashSketch(t *testing.T) {
+	genome := readGenome(t, "../Data/Coronaviruses/SARS-CoV-2_genome.fasta")
+	s := SketchSequence("SARS-CoV-2", genome, 21, 500)
+	if len(s.Hashes) != 500 || !sort.SliceIsSorted(s.Hashes, func(i, j int) bool { return s.Hashes[i] < s.Hashes[j] }) {
+		t.Fatal("expected 500 sorted hashes, got", len(s.Hashes))
+	}
+
+	// the reverse complement has the same canonical k-mers
+	rc := SketchSequence("reverse", ReverseComplement(genome), 21, 500)
+	if j := JaccardEstimate(s, rc); j != 1 || MashDistance(s, rc) != 0 {
+		t.Error("expected identical sketches for a genome and its reverse complement, got Jaccard", j)
+	}
+
+	// the estimate should be close to the exact Jaccard similarity
+	other := readGenome(t, "../Data/Coronaviruses/SARS-CoV_genome.fasta")
+	for _, k := range []int{9, 13} {
+		s1 := SketchSequence("SARS-CoV-2", genome, k, 2000)
+		s2 := SketchSequence("SARS-CoV", other, k, 2000)
+		exact := JaccardIndex(CanonicalFrequencyMap(genome, k), CanonicalFrequencyMap(other, k))
+		if estimate := JaccardEstimate(s1, s2); math.Abs(estimate-exact) > 0.05 {
+			t.Error("For k =", k, "expected a Jaccard estimate near", exact, "got", estimate)
+		}
+	}
+
+	disjoint := SketchSequence("poly-A", strings.Repeat("A", 100), 21, 500)
+	if j, d := JaccardEstimate(s, disjoint), MashDistance(s, disjoint); j != 0 || d != 1 {
+		t.Error("expected Jaccard 0 and distance 1 for disjoint sketches, got", j, d)
+	}
+
+	// k-mers holding N or other ambiguity codes are skipped, so runs of N don't make
+	// unrelated sequences look alike
+	masked := SketchSequence("masked", genome[:2000]+strings.Repeat("N", 1000)+"ACGRTT", 21, 500)
+	unmasked := SketchSequence("unmasked", genome[:2000], 21, 500)
+	if !reflect.DeepEqual(masked.Hashes, unmasked.Hashes) {
+		t.Error("expected k-mers holding N to be left out of the sketch")
+	}
+	polyA := SketchSequence("poly-A", strings.Repeat("A", 100)+strings.Repeat("n", 1000), 21, 500)
+	if j := JaccardEstimate(masked, polyA); j != 0 {
+		t.Error("expected sequences sharing only N runs to have Jaccard 0, got", j)
+	}
+}
+
+func TestMashDistance(t *testing.T) {
+	s1 := Sketch{Name: "a", K: 10, Size: 4, Hashes: []uint64{1, 2, 3, 4}}
+	s2 := Sketch{Name: "b", K: 10, Size: 4, Hashes: []uint64{2, 4, 5, 6}}
+	// the 4 smallest hashes of the union are 1, 2, 3, 4, and 2 and 4 are in both
+	if j := JaccardEstimate(s1, s2); j != 0.5 {
+		t.Error("expected Jaccard estimate 0.5, got", j)
+	}
+	if d, expected := MashDistance(s1, s2), -math.Log(2.0/3.0)/10; math.Abs(d-expected) > 1e-12 {
+		t.Error("expected Mash distance", expected, "got", d)
+	}
+}
+
+func TestSketchSerialization(t *testing.T) {
+	sketches := []Sketch{
+		SketchSequence("first", "ACGTACGGTACCATGACTAGCATCGAT", 5, 8),
+		SketchSequence("second", "TTGACCATGACGATCAG", 5, 100),
+		{Name: "empty", K: 5, Size: 10, Hashes: []uint64{}}}
+
+	var b bytes.Buffer
+	if err := WriteSketches(&b, sketches); err != nil {
+		t.Fatal(err)
+	}
+	v, err := ReadSketches(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(v, sketches) {
+		t.Error("expected", sketches, "got", v)
+	}
+
+	if _, err := ReadSketches(strings.NewReader("#minhash-sketches v1 fnv1a64-fmix64\nx\t5\t2\t3,2\n")); err == nil {
+		t.Error("expected an error for hashes out of order")
+	}
+	if _, err := ReadSketches(strings.NewReader("name\t5\t2\t1\n")); err == nil {
+		t.Error("expected an error for a missing header")
+	}
+
+	// errors reading the file stay visible to errors.As
+	var pathErr *os.PathError
+	if _, err := ReadSketchFile(t.TempDir()); !errors.As(err, &pathErr) {
+		t.Error("expected a *os.PathError reading a directory, got", err)
+	}
+}
+
+/********************************************
+ Seed and Extend Tests
+*********************************************/
+
+func TestIndexKmers(t *testing.T) {
+	index := IndexKmers("ACGTACGA", 3)
+	expected := map[string][]int{"ACG": {0, 4}, "CGT": {1}, "GTA": {2}, "TAC": {3}, "CGA": {5}}
+	if !reflect.DeepEqual(index.Positions, expected) {
+		t.Error("expected", expected, "got", index.Positions)
+	}
+
+	hits := FindSeedHits("TACGA", index)
+	expectedHits := []SeedHit{{0, 3}, {1, 0}, {1, 4}, {2, 5}}
+	if !reflect.DeepEqual(hits, expectedHits) {
+		t.Error("expected", expectedHits, "got", hits)
+	}
+}
+
+func TestSeedAndExtend(t *testing.T) {
+	genome := readGenome(t, "../Data/Coronaviruses/SARS-CoV-2_genome.fasta")
+	subject := genome[1000:3000]
+
+	// the query is a piece of the subject with two substitutions and a 3-base deletion,
+	// flanked by sequence from elsewhere in the genome
+	piece := []byte(subject[500:900])
+	piece[100] = 'A'
+	piece[250] = 'C'
+	piece = append(piece[:180], piece[183:]...)
+	query := genome[20000:20050] + string(piece) + genome[25000:25050]
+
+	hsps := SeedAndExtend(query, IndexKmers(subject, 11), DefaultSearchMatch, DefaultSearchMismatch, DefaultSearchGap, DefaultSearchParameters())
+	if len(hsps) == 0 {
+		t.Fatal("expected an HSP")
+	}
+	best := hsps[0]
+	if best.QueryStart > 50 || best.QueryEnd < 50+len(piece) || best.SubjectStart > 500 || best.SubjectEnd < 900 {
+		t.Error("expected the best HSP to cover the piece, got", best.QueryStart, best.QueryEnd, best.SubjectStart, best.SubjectEnd)
+	}
+
+	// the alignment must spell out the reported ranges and have the reported score
+	for _, hsp := range hsps {
+		if strings.Replace(hsp.Alignment[0], "-", "", -1) != query[hsp.QueryStart:hsp.QueryEnd] ||
+			strings.Replace(hsp.Alignment[1], "-", "", -1) != subject[hsp.SubjectStart:hsp.SubjectEnd] {
+			t.Error("HSP alignment does not spell out its ranges:", hsp)
+		}
+		if score := computeAffineScore(hsp.Alignment, DefaultSearchMatch, DefaultSearchMismatch, DefaultSearchGap, DefaultSearchGap); score != hsp.Score {
+			t.Error("expected HSP score", hsp.Score, "to match its alignment's score", score)
+		}
+	}
+
+	// on this easy case, the best HSP is an optimal local alignment
+	table := LocalScoreTable(query, subject, DefaultSearchMatch, DefaultSearchMismatch, DefaultSearchGap)
+	row, col := MaxTableCell(table)
+	if best.Score != table[row][col] {
+		t.Error("expected best HSP score", table[row][col], "got", best.Score)
+	}
+
+	if hsps := SeedAndExtend(strings.Repeat("A", 50), IndexKmers(subject, 11), DefaultSearchMatch, DefaultSearchMismatch, DefaultSearchGap, DefaultSearchParameters()); len(hsps) != 0 {
+		t.Error("expected no HSPs for an unrelated query, got", hsps)
+	}
+}
+
+/********************************************
+ Hamming Distance Tests
+*********************************************/
+
+type hammingTestPair struct {
+	str1  string
+	str2  string
+	hDist int
+}
+
+var hammingTests = []hammingTestPair{
+	{"", "", 0},
+	{"A", "A", 0},
+	{"A", "T", 1},
+	{"GGGCCGTTGGT", "GGACCGTTGAC", 3},
+	{"ACGTACGT", "TGCATGCA", 8},
+	{"AAAA", "aaaa", 4},
+	{"CTTGAAGTGGACCTCTAGTTCCTCTACAAAGAACAGGTTGACCTGTCGCGAAG",
+		"ATGCCTTACCTAGATGCAATGACGGACGTATTCCTTTTGCCTCAACGGCTCCT", 43}}
+
+func TestHammingDistance(t *testing.T) {
+	for _, pair := range hammingTests {
+		v := HammingDistance(pair.str1, pair.str2)
+		if v != pair.hDist {
+			t.Error(
+				"For", pair.str1,
+				"and", pair.str2,
+				"expected", strconv.Itoa(pair.hDist),
+				"got", strconv.Itoa(v),
+			)
+		}
+	}
+}
+
+func TestApproximatePatternMatching(t *testing.T) {
+	genome := "CGCCCGAATCCAGAACGCATTCCCATATTTCGGGACCACTGGCCTCCACGGTACGGACGTCAATCAAATGCCTAGCGGCTTGTGGTTTCTCCTACGCTCC"
+	v := ApproximatePatternMatching("ATTCTGGA", genome, 3)
+	expected := []int{6, 7, 26, 27, 78}
+	if !reflect.DeepEqual(v, expected) {
+		t.Error("expected", expected, "got", v)
+	}
+	if count := ApproximatePatternCount("GAGG", "TTTAGAGCCTTCAGAGG", 2); count != 4 {
+		t.Error("expected 4 approximate occurrences of GAGG, got", count)
+	}
+	if v := ApproximatePatternMatching("ACGTACGT", "ACG", 1); len(v) != 0 {
+		t.Error("expected no matches in a genome shorter than the pattern, got", v)
+	}
+}
+
+func TestNeighbors(t *testing.T) {
+	v := Neighbors("ACG", 1)
+	expected := []string{"AAG", "ACA", "ACC", "ACG", "ACT", "AGG", "ATG", "CCG", "GCG", "TCG"}
+	if !reflect.DeepEqual(v, expected) {
+		t.Error("expected", expected, "got", v)
+	}
+
+	// a 4-mer has 1 + 4*3 + 6*9 strings within two mismatches
+	v = Neighbors("GATT", 2)
+	if len(v) != 67 {
+		t.Error("expected 67 neighbors of GATT, got", len(v))
+	}
+	for _, text := range v {
+		if HammingDistance("GATT", text) > 2 {
+			t.Error("neighbor", text, "is too far from GATT")
+		}
+	}
+}
+
+/********************************************
+ LCS length Tests
+*********************************************/
+
+type lcsLengthTestpair struct {
+	str1      string
+	str2      string
+	lcsLength int
+}
+
+var lcsLengthTests = []lcsLengthTestpair{
+	{"ATGCGGCTAGCTTAGCCTAGATCGATCGGCTAGCTAGCTAGCCGAGGCTCTCGATCGATCGCGCTAGG",
+		"ATGCGGCTGGCTTAGCCTAGTTCGATCGCGTTCGTAGCTATAGAGCTAGCTAGATCGATCGCGCTAGG", 59},
+	{"TTGCGGAGCTAGGGATCCGATCGAATATCGATATTCGATCGGGAACACAGATCGAT",
+		"GGTACATCGATTCTAGATTCTATAGCGCGCTTCGATCGATTCGATCGATCGAAAAG", 37},
+	{"TTGCGGAGCTAGGGATCCGATCGAATATCGATATTCGATCGGGAACACAGATCGAT",
+		"TTGCGGAGCTAGGGATCCGATCGAATATCGATATTCGATCGGGAACACAGATCGAT", 56},
+	{"TTGCGGAGCTAGGGATCCGATCGAATATCGATATTCGATCGGGAACACAGATCGAT",
+		"TTGCGGAGCTAGGGATCCGATCGAATATCGATATAGCATCGGGAACACAGATCGAT", 54},
+	{"AGCT",
+		"TCGA", 1},
+	{"AATT",
+		"CCGG", 0},
+	{"AGCT",
+		"TGAC", 2},
+	{"---ATCGATC--ATCGATT-GGACAT",
+		"--ATGATGCATC---ATCGGTAG---GCTTAGCTTTTAG", 20}}
+
+func TestLCSLength(t *testing.T) {
+	for _, pair := range lcsLengthTests {
+		v := LCSLength(pair.str1, pair.str2)
+		if v != pair.lcsLength {
+			t.Error(
+				"For", pair.str1,
+				"and", pair.str2,
+				"expected", strconv.Itoa(pair.lcsLength),
+				"got", strconv.Itoa(v),
+			)
+		}
+	}
+}
+
+/********************************************
+ Levenshtein Distance Tests
+*********************************************/
+
+type levenshteinDistanceTestpair struct {
+	str1    string
+	str2    string
+	levDist int
+}
+
+var levenshteinDistanceTests = []levenshteinDistanceTestpair{
+	{"A--T",
+		"A--T", 0},
+	{"ATGCGGCTAGCTTAGCCTAGATCGATCGGCTAGCTAGCTAGCCGAGGCTCTCGATCGATCGCGCTAGG",
+		"ATGCGGCTGGCTTAGCCTAGTTCGATCGCGTTCGTAGCTATAGAGCTAGCTAGATCGATCGCGCTAGG", 13},
+	{"TTGCGGAGCTAGGGATCCGATCGAATATCGATATTCGATCGGGAACACAGATCGAT",
+		"GGTACATCGATTCTAGATTCTATAGCGCGCTTCGATCGATTCGATCGATCGAAAAG", 29},
+	{"TTGCGGAGCTAGGGA---TCCGATCGAATATCGATAT---TCGATCGGGAACAC---AGATCGAT",
+		"TTGCG-GAGCTAGGGATCCGATCGAATATCGATATTCGATCGGGAA---CACAGATCGAT", 13},
+	{"TTGCGGAGCTAGGGATCCGATCGAATATCGATATTCGATCGGGAACACAGATCGAT",
+		"TTGCGGAGCTAGGGATCCGATCGAATATCGATATAGCATCGGGAACACAGATCGAT", 3},
+	{"TTGCGGAG-CTAGGGATCCGATCGAATA---TCGATATTCGATCGGGAACACAGATCGAT",
+		"TT--GCGGAGCTAGGGATCCGATCGAATATCGATATAGCAT----CGGGAAC--ACAGATCGAT", 15}}
+
+func TestEditDistance(t *testing.T) {
+	for _, pair := range levenshteinDistanceTests {
+		v := EditDistance(pair.str1, pair.str2)
+		if v != pair.levDist {
+			t.Error(
+				"For", pair.str1,
+				"and", pair.str2,
+				"expected", strconv.Itoa(pair.levDist),
+				"got", strconv.Itoa(v),
+			)
+		}
+	}
+}
+
+func TestBitParallelEditDistance(t *testing.T) {
+	for _, pair := range levenshteinDistanceTests {
+		if v := BitParallelEditDistance(pair.str1, pair.str2); v != pair.levDist {
+			t.Error("For", pair.str1, "and", pair.str2, "expected", pair.levDist, "got", v)
+		}
+	}
+
+	// random strings whose lengths cross the 64-symbol block boundaries
+	rng := rand.New(rand.NewSource(1))
+	randomString := func(n int, alphabet string) string {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = alphabet[rng.Intn(len(alphabet))]
+		}
+		return string(b)
+	}
+	for trial := 0; trial < 300; trial++ {
+		alphabet := []string{"AC", "ACGT", "ACDEFGHIKLMNPQRSTVWY"}[trial%3]
+		str1 := randomString(1+rng.Intn(200), alphabet)
+		str2 := randomString(1+rng.Intn(200), alphabet)
+		expected := EditMatrix(str1, str2)[len(str1)][len(str2)]
+		if v := BitParallelEditDistance(str1, str2); v != expected {
+			t.Fatal("For", str1, "and", str2, "expected", expected, "got", v)
+		}
+	}
+
+	if v := BitParallelEditDistance("", "ACGT"); v != 4 {
+		t.Error("expected distance 4 from the empty string, got", v)
+	}
+}
+
+/********************************************
+ Local Alignment Score Tests
+*********************************************/
+
+type LocalAlignmentInput struct {
+	str1     string
+	str2     string
+	match    float64
+	mismatch float64
+	gap      float64
+}
+
+type localscoretestpair struct {
+	input       LocalAlignmentInput
+	scoreMatrix [][]float64
+}
+
+var localScoreTests = []localscoretestpair{
+	{LocalAlignmentInput{"A",
+		"A",
+		1.0, 0.5, 1.0},
+		[][]float64{[]float64{0, 0}, []float64{0, 1}}},
+
+	{LocalAlignmentInput{"GAAC",
+		"CAAG",
+		1.0, 1.0, 0.5},
+		[][]float64{[]float64{0, 0, 0, 0, 0},
+			[]float64{0, 0, 0, 0, 1},
+			[]float64{0, 0, 1, 1, 0.5},
+			[]float64{0, 0, 1, 2, 1.5},
+			[]float64{0, 1, 0.5, 1.5, 1}}},
+
+	{LocalAlignmentInput{"GAAC",
+		"CAAG",
+		1.0, 0.5, 1.0},
+		[][]float64{[]float64{0, 0, 0, 0, 0},
+			[]float64{0, 0, 0, 0, 1},
+			[]float64{0, 0, 1, 1, 0},
+			[]float64{0, 0, 1, 2, 1},
+			[]float64{0, 1, 0, 1, 1.5}}},
+
+	{LocalAlignmentInput{"TACG",
+		"CACG",
+		1.0, 1.0, 0.5},
+		[][]float64{[]float64{0, 0, 0, 0, 0},
+			[]float64{0, 0, 0, 0, 0},
+			[]float64{0, 0, 1, 0.5, 0},
+			[]float64{0, 1, 0.5, 2, 1.5},
+			[]float64{0, 0.5, 0, 1.5, 3}}},
+
+	{LocalAlignmentInput{"TACGG",
+		"CACG",
+		1.0, 0.5, 1.0},
+		[][]float64{[]float64{0, 0, 0, 0, 0},
+			[]float64{0, 0, 0, 0, 0},
+			[]float64{0, 0, 1, 0, 0},
+			[]float64{0, 1, 0, 2, 1},
+			[]float64{0, 0, 0.5, 1, 3},
+			[]float64{0, 0, 0, 0, 2}}},
+
+	{LocalAlignmentInput{"ATCGATCGT",
+		"ATCGGCTAC",
+		1.0, 1.0, 0.5},
+		[][]float64{[]float64{0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+			[]float64{0, 1, 0.5, 0, 0, 0, 0, 0, 1, 0.5},
+			[]float64{0, 0.5, 2, 1.5, 1, 0.5, 0, 1, 0.5, 0},
+			[]float64{0, 0, 1.5, 3, 2.5, 2, 1.5, 1, 0.5, 1.5},
+			[]float64{0, 0, 1, 2.5, 4, 3.5, 3, 2.5, 2, 1.5},
+			[]float64{0, 1, 0.5, 2, 3.5, 3, 2.5, 2, 3.5, 3},
+			[]float64{0, 0.5, 2, 1.5, 3, 2.5, 2, 3.5, 3, 2.5},
+			[]float64{0, 0, 1.5, 3, 2.5, 2, 3.5, 3, 2.5, 4},
+			[]float64{0, 0, 1, 2.5, 4, 3.5, 3, 2.5, 2, 3.5},
+			[]float64{0, 0, 1, 2, 3.5, 3, 2.5, 4, 3.5, 3}}},
+
+	{LocalAlignmentInput{"ATCGATCGT",
+		"AAC",
+		1.0, 1.0, 0.5},
+		[][]float64{[]float64{0, 0, 0, 0},
+			[]float64{0, 1, 1, 0.5},
+			[]float64{0, 0.5, 0.5, 0},
+			[]float64{0, 0, 0, 1.5},
+			[]float64{0, 0, 0, 1},
+			[]float64{0, 1, 1, 0.5},
+			[]float64{0, 0.5, 0.5, 0},
+			[]float64{0, 0, 0, 1.5},
+			[]float64{0, 0, 0, 1},
+			[]float64{0, 0, 0, 0.5}}}}
+
+func TestLocalScoreTable(t *testing.T) {
+	for _, pair := range localScoreTests {
+		v := LocalScoreTable(pair.input.str1, pair.input.str2, pair.input.match, pair.input.mismatch, pair.input.gap)
+		if !reflect.DeepEqual(v, pair.scoreMatrix) {
+			t.Error(
+				"For", pair.input,
+				"expected scoring matrix", pair.scoreMatrix,
+				"got", v,
+			)
+		}
+	}
+}
+
+/********************************************
+ Local Alignment Tests
+*********************************************/
+
+type Solution struct {
+	score  float64
+	start0 int
+	end0   int
+	start1 int
+	end1   int
+}
+
+type testpair struct {
+	input LocalAlignmentInput
+	sol   Solution
+}
+
+var tests = []testpair{
+	{LocalAlignmentInput{"GAAC",
+		"CAAG",
+		1.0, 1.0, 0.5},
+		Solution{2.000, 1, 3, 1, 3}},
+
+	{LocalAlignmentInput{"TAAC",
+		"TAAG",
+		1.0, 1.0, 0.5},
+		Solution{3.000, 0, 3, 0, 3}},
+
+	{LocalAlignmentInput{"A",
+		"A",
+		1.0, 0.5, 1.0},
+		Solution{1.000, 0, 1, 0, 1}},
 
 	{LocalAlignmentInput{"TACGG",
 		"CACGTG",
@@ -588,6 +1758,235 @@ func TestLocalAlignment(t *testing.T) {
 	}
 }
 
+/********************************************
+ Affine Gap Alignment Tests
+*********************************************/
+
+type AffineAlignmentInput struct {
+	str1      string
+	str2      string
+	match     float64
+	mismatch  float64
+	gapOpen   float64
+	gapExtend float64
+}
+
+type affinetestpair struct {
+	input    AffineAlignmentInput
+	solScore float64
+}
+
+var globalAffineTests = []affinetestpair{
+	{AffineAlignmentInput{"A",
+		"A",
+		1.0, 0.5, 2.0, 1.0},
+		1.000},
+
+	{AffineAlignmentInput{"ACGTTTACG",
+		"ACGACG",
+		1.0, 1.0, 2.0, 0.5},
+		3.000},
+
+	{AffineAlignmentInput{"GATTACA",
+		"GA",
+		1.0, 1.0, 3.0, 0.5},
+		-3.000},
+
+	{AffineAlignmentInput{"TTAGCTTA",
+		"TTAGGCTTA",
+		1.0, 2.0, 1.5, 0.5},
+		6.500}}
+
+type localaffinetestpair struct {
+	input AffineAlignmentInput
+	sol   Solution
+}
+
+var localAffineTests = []localaffinetestpair{
+	{AffineAlignmentInput{"CCACGTACGTTTACGTACGCC",
+		"GGACGTACGACGTACGGG",
+		1.0, 1.0, 2.0, 0.5},
+		Solution{11.000, 2, 19, 2, 16}},
+
+	{AffineAlignmentInput{"GAAC",
+		"CAAG",
+		1.0, 1.0, 2.0, 0.5},
+		Solution{2.000, 1, 3, 1, 3}}}
+
+//computeAffineScore returns the score of an alignment where a run of L gap
+//symbols in either row is penalized by gapOpen + (L-1)*gapExtend.
+func computeAffineScore(alignment [2]string, match, mismatch, gapOpen, gapExtend float64) float64 {
+	score := 0.0
+	inGap := [2]bool{false, false}
+
+	for i := range alignment[0] {
+		c0 := alignment[0][i]
+		c1 := alignment[1][i]
+		if c0 == '-' || c1 == '-' {
+			row := 0
+			if c1 == '-' {
+				row = 1
+			}
+			if inGap[row] {
+				score -= gapExtend
+			} else {
+				score -= gapOpen
+			}
+			inGap[row] = true
+			inGap[1-row] = false
+		} else {
+			if c0 == c1 {
+				score += match
+			} else {
+				score -= mismatch
+			}
+			inGap = [2]bool{false, false}
+		}
+	}
+	return score
+}
+
+func TestGlobalAffineAlignment(t *testing.T) {
+	for _, pair := range globalAffineTests {
+		in := pair.input
+		v := GlobalAffineAlignment(in.str1, in.str2, in.match, in.mismatch, in.gapOpen, in.gapExtend)
+		score := computeAffineScore(v, in.match, in.mismatch, in.gapOpen, in.gapExtend)
+		if score != pair.solScore {
+			t.Error(
+				"For", in,
+				"expected alignment with score", strconv.FormatFloat(pair.solScore, 'f', 3, 64),
+				"got", v, "with a score of", strconv.FormatFloat(score, 'f', 3, 64),
+			)
+		}
+	}
+
+	// with equal opening and extension penalties we should match linear gap alignment
+	for _, pair := range globalScoreTests {
+		in := pair.input
+		v := GlobalAffineAlignment(in.str1, in.str2, in.match, in.mismatch, in.gap, in.gap)
+		score := computeAffineScore(v, in.match, in.mismatch, in.gap, in.gap)
+		linearScore := pair.scoreMatrix[len(in.str1)][len(in.str2)]
+		if score != linearScore {
+			t.Error(
+				"For", in,
+				"expected alignment with linear gap score", linearScore,
+				"got", v, "with a score of", score,
+			)
+		}
+	}
+}
+
+func TestLocalAffineAlignment(t *testing.T) {
+	for _, pair := range localAffineTests {
+		in := pair.input
+		optAlignment, start0, end0, start1, end1 := LocalAffineAlignment(in.str1, in.str2, in.match, in.mismatch, in.gapOpen, in.gapExtend)
+		score := computeAffineScore(optAlignment, in.match, in.mismatch, in.gapOpen, in.gapExtend)
+		v := Solution{score, start0, end0, start1, end1}
+		if !reflect.DeepEqual(v, pair.sol) {
+			t.Error(
+				"For", in,
+				"expected", pair.sol,
+				"got", optAlignment, "with", v,
+			)
+		}
+	}
+}
+
+/********************************************
+ Scoring Matrix Tests
+*********************************************/
+
+type scoringMatrixTestpair struct {
+	matrix ScoringMatrix
+	a      byte
+	b      byte
+	score  float64
+}
+
+var scoringMatrixTests = []scoringMatrixTestpair{
+	{BLOSUM62, 'A', 'A', 4},
+	{BLOSUM62, 'W', 'W', 11},
+	{BLOSUM62, 'W', 'C', -2},
+	{BLOSUM62, 'i', 'v', 3},
+	{BLOSUM62, 'U', 'A', -4},
+	{BLOSUM45, 'C', 'C', 12},
+	{BLOSUM80, 'E', 'C', -5},
+	{PAM250, 'W', 'W', 17},
+	{PAM250, 'F', 'Y', 7}}
+
+func TestScoringMatrix(t *testing.T) {
+	for _, pair := range scoringMatrixTests {
+		v := pair.matrix.Score(pair.a, pair.b)
+		if v != pair.score {
+			t.Error(
+				"For", pair.matrix.Name,
+				"and", string(pair.a), string(pair.b),
+				"expected", pair.score,
+				"got", v,
+			)
+		}
+	}
+
+	// the built-in matrices should be symmetric
+	for _, m := range []ScoringMatrix{BLOSUM45, BLOSUM62, BLOSUM80, PAM250} {
+		for a := range m.Scores {
+			for b := range m.Scores[a] {
+				if m.Scores[a][b] != m.Scores[b][a] {
+					t.Error(m.Name, "is not symmetric at", string(a), string(b))
+				}
+			}
+		}
+	}
+}
+
+func TestParseScoringMatrix(t *testing.T) {
+	text := "# a tiny DNA matrix\n   A  C\nA  2 -1\nC -1  3\n"
+	m, err := ParseScoringMatrix("tiny", strings.NewReader(text))
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if m.Score('A', 'C') != -1 || m.Score('c', 'c') != 3 {
+		t.Error("For", text, "got", m.Scores)
+	}
+
+	_, err = ParseScoringMatrix("bad", strings.NewReader("   A  C\nA  2\n"))
+	if err == nil {
+		t.Error("expected an error for a row with too few scores")
+	}
+}
+
+func computeMatrixScore(alignment [2]string, scoring ScoringMatrix, gap float64) float64 {
+	score := 0.0
+	for i := range alignment[0] {
+		if alignment[0][i] == '-' || alignment[1][i] == '-' {
+			score -= gap
+		} else {
+			score += scoring.Score(alignment[0][i], alignment[1][i])
+		}
+	}
+	return score
+}
+
+func TestGlobalAlignmentWithMatrix(t *testing.T) {
+	str0 := "HEAGAWGHEE"
+	str1 := "PAWHEAE"
+	gap := 8.0
+	v := GlobalAlignmentWithMatrix(str0, str1, BLOSUM62, gap)
+	table := GlobalScoreTableWithMatrix(str0, str1, BLOSUM62, gap)
+	expected := table[len(str0)][len(str1)]
+	if score := computeMatrixScore(v, BLOSUM62, gap); score != expected {
+		t.Error("expected alignment with score", expected, "got", v, "with score", score)
+	}
+}
+
+func TestLocalAlignmentWithMatrix(t *testing.T) {
+	v, start0, end0, start1, end1 := LocalAlignmentWithMatrix("MEANLY", "PLEASANTLY", BLOSUM62, 5)
+	score := computeMatrixScore(v, BLOSUM62, 5)
+	if score != 16 || start0 != 2 || end0 != 6 || start1 != 5 || end1 != 10 {
+		t.Error("For MEANLY and PLEASANTLY expected score 16 at 2 6 5 10, got", v, score, start0, end0, start1, end1)
+	}
+}
+
 /********************************************
  Shared k-mers Tests
 *********************************************/
@@ -632,6 +2031,141 @@ func TestCountSharedKmers(t *testing.T) {
 	}
 }
 
+/********************************************
+ Reverse Complement and Canonical k-mer Tests
+*********************************************/
+
+func TestReverseComplement(t *testing.T) {
+	tests := [][2]string{
+		{"ACGT", "ACGT"},
+		{"AAACCG", "CGGTTT"},
+		{"acgU", "Acgt"},
+		{"ARYKMSWBDHVN-", "-NBDHVWSKMRYT"},
+		{"", ""}}
+	for _, test := range tests {
+		if v := ReverseComplement(test[0]); v != test[1] {
+			t.Error("For", test[0], "expected", test[1], "got", v)
+		}
+	}
+	if v := CanonicalKmer("TTG"); v != "CAA" {
+		t.Error("expected canonical k-mer CAA for TTG, got", v)
+	}
+}
+
+func TestCountSharedCanonicalKmers(t *testing.T) {
+	// the second string is the reverse complement of the first
+	if v := CountSharedKmers("AAAC", "GTTT", 2); v != 0 {
+		t.Error("expected no shared forward 2-mers, got", v)
+	}
+	if v := CountSharedCanonicalKmers("AAAC", "GTTT", 2); v != 3 {
+		t.Error("expected 3 shared canonical 2-mers, got", v)
+	}
+	if v, expected := CanonicalFrequencyMap("AAAC", 2), map[string]int{"AA": 2, "AC": 1}; !reflect.DeepEqual(v, expected) {
+		t.Error("expected", expected, "got", v)
+	}
+
+	// canonical sharing never finds fewer k-mers than forward sharing
+	for _, pair := range sharedKMersTests {
+		if v := CountSharedCanonicalKmers(pair.str1, pair.str2, pair.k); v < pair.sharedKmers {
+			t.Error("For", pair.str1, "and", pair.str2, "expected at least", pair.sharedKmers, "got", v)
+		}
+	}
+}
+
+func TestSharedKmerReport(t *testing.T) {
+	v := SharedKmerReport("AAACGT", "ACGTTTTT", 3)
+	expected := []SharedKmer{
+		{Kmer: "AAA", Forward1: 1, Forward2: 0, Reverse2: 3, Opposite: 1, Orientation: OppositeStrand},
+		{Kmer: "AAC", Forward1: 1, Reverse2: 1, Opposite: 1, Orientation: OppositeStrand},
+		{Kmer: "ACG", Forward1: 1, Reverse1: 1, Forward2: 1, Reverse2: 1, Same: 2, Opposite: 2, Orientation: BothStrands}}
+	if !reflect.DeepEqual(v, expected) {
+		t.Error("expected", expected, "got", v)
+	}
+}
+
+/********************************************
+ Packed k-mer Tests
+*********************************************/
+
+func TestPackedKmers(t *testing.T) {
+	code, ok := EncodeKmer("ACGT")
+	if !ok || code != 0x1B {
+		t.Error("expected ACGT to be packed as 0x1B, got", code, ok)
+	}
+	if _, ok := EncodeKmer("ACNT"); ok {
+		t.Error("expected ACNT not to be packable")
+	}
+	long := strings.Repeat("TGCA", 8)
+	if code, _ := EncodeKmer(long); DecodeKmer(code, 32) != long {
+		t.Error("expected", long, "to survive packing, got", DecodeKmer(code, 32))
+	}
+
+	genome := readGenome(t, "../Data/Coronaviruses/SARS-CoV-2_genome.fasta")
+	for _, k := range []int{1, 5, 12, 31, 32} {
+		expected := FrequencyMap(genome[:5000], k)
+		if v := DecodeFrequencyMap(PackedFrequencyMap(genome[:5000], k), k); !reflect.DeepEqual(v, expected) {
+			t.Error("For k =", k, "packed frequency map differs from FrequencyMap")
+		}
+		expected = CanonicalFrequencyMap(genome[:5000], k)
+		if v := DecodeFrequencyMap(CanonicalPackedFrequencyMap(genome[:5000], k), k); !reflect.DeepEqual(v, expected) {
+			t.Error("For k =", k, "canonical packed frequency map differs from CanonicalFrequencyMap")
+		}
+	}
+
+	// k-mers overlapping an unpackable symbol are skipped, and case is ignored
+	if v, expected := DecodeFrequencyMap(PackedFrequencyMap("acgNacgt", 3), 3), map[string]int{"ACG": 2, "CGT": 1}; !reflect.DeepEqual(v, expected) {
+		t.Error("expected", expected, "got", v)
+	}
+}
+
+func TestCountSharedKmersPacked(t *testing.T) {
+	for _, pair := range sharedKMersTests {
+		if pair.k > MaxPackedK {
+			continue
+		}
+		if v := CountSharedKmersPacked(pair.str1, pair.str2, pair.k); v != pair.sharedKmers {
+			t.Error("For", pair.str1, "and", pair.str2, "with k =", pair.k, "expected", pair.sharedKmers, "got", v)
+		}
+		if v, expected := CountSharedCanonicalKmersPacked(pair.str1, pair.str2, pair.k), CountSharedCanonicalKmers(pair.str1, pair.str2, pair.k); v != expected {
+			t.Error("For", pair.str1, "and", pair.str2, "with k =", pair.k, "expected", expected, "canonical k-mers, got", v)
+		}
+	}
+}
+
+func BenchmarkFrequencyMap(b *testing.B) {
+	genome := readGenome(b, "../Data/Coronaviruses/SARS-CoV-2_genome.fasta")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		FrequencyMap(genome, 21)
+	}
+}
+
+func BenchmarkPackedFrequencyMap(b *testing.B) {
+	genome := readGenome(b, "../Data/Coronaviruses/SARS-CoV-2_genome.fasta")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		PackedFrequencyMap(genome, 21)
+	}
+}
+
+func BenchmarkCountSharedKmers(b *testing.B) {
+	genome0 := readGenome(b, "../Data/Coronaviruses/SARS-CoV_genome.fasta")
+	genome1 := readGenome(b, "../Data/Coronaviruses/SARS-CoV-2_genome.fasta")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		CountSharedKmers(genome0, genome1, 21)
+	}
+}
+
+func BenchmarkCountSharedKmersPacked(b *testing.B) {
+	genome0 := readGenome(b, "../Data/Coronaviruses/SARS-CoV_genome.fasta")
+	genome1 := readGenome(b, "../Data/Coronaviruses/SARS-CoV-2_genome.fasta")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		CountSharedKmersPacked(genome0, genome1, 21)
+	}
+}
+
 /********************************************
  LCS Tests
 *********************************************/
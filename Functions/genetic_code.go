@@ -0,0 +1,127 @@
+package Functions
+
+import "strings"
+
+//GeneticCode is a translation table in the format used by NCBI. AminoAcids lists the amino
+//acid (or "*" for stop) encoded by each of the 64 codons ordered TTT, TTC, TTA, TTG, TCT, ...,
+//GGG, and Starts marks the codons that may begin translation with an "M".
+type GeneticCode struct {
+	ID         int
+	Name       string
+	AminoAcids string
+	Starts     string
+}
+
+//The genetic codes most often needed, numbered as in NCBI's translation tables.
+var (
+	StandardCode = GeneticCode{1, "Standard",
+		"FFLLSSSSYY**CC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		"---M------**--*----M---------------M----------------------------"}
+	VertebrateMitochondrialCode = GeneticCode{2, "Vertebrate Mitochondrial",
+		"FFLLSSSSYY**CCWWLLLLPPPPHHQQRRRRIIMMTTTTNNKKSS**VVVVAAAADDEEGGGG",
+		"----------**--------------------MMMM----------**---M------------"}
+	YeastMitochondrialCode = GeneticCode{3, "Yeast Mitochondrial",
+		"FFLLSSSSYY**CCWWTTTTPPPPHHQQRRRRIIMMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		"----------**----------------------MM---------------M------------"}
+	MoldMitochondrialCode = GeneticCode{4, "Mold, Protozoan, and Coelenterate Mitochondrial; Mycoplasma; Spiroplasma",
+		"FFLLSSSSYY**CCWWLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		"--MM------**-------M------------MMMM---------------M------------"}
+	InvertebrateMitochondrialCode = GeneticCode{5, "Invertebrate Mitochondrial",
+		"FFLLSSSSYY**CCWWLLLLPPPPHHQQRRRRIIMMTTTTNNKKSSSSVVVVAAAADDEEGGGG",
+		"---M------**--------------------MMMM---------------M------------"}
+	BacterialCode = GeneticCode{11, "Bacterial, Archaeal and Plant Plastid",
+		"FFLLSSSSYY**CC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		"---M------**--*----M------------MMMM---------------M------------"}
+)
+
+//GeneticCodeByID returns the built-in genetic code with an NCBI table number, along with
+//whether it was found.
+func GeneticCodeByID(id int) (GeneticCode, bool) {
+	for _, code := range []GeneticCode{StandardCode, VertebrateMitochondrialCode, YeastMitochondrialCode,
+		MoldMitochondrialCode, InvertebrateMitochondrialCode, BacterialCode} {
+		if code.ID == id {
+			return code, true
+		}
+	}
+	return GeneticCode{}, false
+}
+
+//nucleotideIndex returns the index (T = 0, C = 1, A = 2, G = 3) of a nucleotide in the
+//ordering used by NCBI genetic code tables, or -1 for any other symbol. U is read as T.
+func nucleotideIndex(c byte) int {
+	switch upperSymbol(c) {
+	case 'T', 'U':
+		return 0
+	case 'C':
+		return 1
+	case 'A':
+		return 2
+	case 'G':
+		return 3
+	}
+	return -1
+}
+
+//codonIndex returns the position of a codon in the NCBI ordering, or -1 if the codon
+//isn't made of three unambiguous nucleotides.
+func codonIndex(codon string) int {
+	if len(codon) != 3 {
+		return -1
+	}
+	index := 0
+	for i := 0; i < 3; i++ {
+		n := nucleotideIndex(codon[i])
+		if n < 0 {
+			return -1
+		}
+		index = 4*index + n
+	}
+	return index
+}
+
+//TranslateCodon takes a codon and returns the amino acid it encodes under the genetic code,
+//"*" for a stop codon, or "X" if the codon holds gaps or ambiguous symbols.
+func (code GeneticCode) TranslateCodon(codon string) byte {
+	index := codonIndex(codon)
+	if index < 0 {
+		return 'X'
+	}
+	return code.AminoAcids[index]
+}
+
+//IsStart returns true if a codon may begin translation under the genetic code.
+func (code GeneticCode) IsStart(codon string) bool {
+	index := codonIndex(codon)
+	return index >= 0 && code.Starts[index] == 'M'
+}
+
+//IsStop returns true if a codon ends translation under the genetic code.
+func (code GeneticCode) IsStop(codon string) bool {
+	return code.TranslateCodon(codon) == '*'
+}
+
+//Translate takes a DNA (or RNA) string and returns the protein encoded by its codons,
+//starting at the first symbol. Stop codons are translated as "*", and any incomplete
+//codon at the end is ignored.
+func (code GeneticCode) Translate(dna string) string {
+	var protein strings.Builder
+	for i := 0; i+3 <= len(dna); i += 3 {
+		protein.WriteByte(code.TranslateCodon(dna[i : i+3]))
+	}
+	return protein.String()
+}
+
+//TranslateToStop is like Translate, but it stops before the first stop codon.
+func (code GeneticCode) TranslateToStop(dna string) string {
+	protein := code.Translate(dna)
+	if i := strings.IndexByte(protein, '*'); i >= 0 {
+		return protein[:i]
+	}
+	return protein
+}
+
+//TranslateCodon takes a codon and returns the amino acid it encodes under the standard
+//genetic code, "*" for a stop codon, or "X" if the codon holds gaps or ambiguous symbols.
+func TranslateCodon(codon string) byte {
+	return StandardCode.TranslateCodon(codon)
+}
@@ -13,6 +13,13 @@ func GlobalAlignment(str0, str1 string, match, mismatch, gap float64) Alignment
 	return optAlignment
 }
 
+//GlobalAlignmentWithMatrix takes two strings, a substitution matrix such as BLOSUM62, and a gap
+//penalty. It returns a maximum score global alignment of the strings under this scoring.
+func GlobalAlignmentWithMatrix(str0, str1 string, scoring ScoringMatrix, gap float64) Alignment {
+	backtrack := GlobalBacktrackWithMatrix(str0, str1, scoring, gap)
+	return OutputGlobalAlignment(str0, str1, backtrack)
+}
+
 func OutputGlobalAlignment(str0, str1 string, backtrack [][]string) Alignment {
 	var a Alignment // array of two strings
 	//a[0] = top row (str0), a[1] = bottom row (str1)
@@ -51,6 +58,20 @@ func OutputGlobalAlignment(str0, str1 string, backtrack [][]string) Alignment {
 }
 
 func GlobalBacktrack(str0, str1 string, match, mismatch, gap float64) [][]string {
+	// let's get the scoring matrix values
+	scoreTable := GlobalScoreTable(str0, str1, match, mismatch, gap)
+	return globalBacktrack(str0, str1, scoreTable, gap)
+}
+
+//GlobalBacktrackWithMatrix takes two strings, a substitution matrix, and a gap penalty.
+//It returns a 2-D slice of backtracking pointers for global alignment under this scoring.
+func GlobalBacktrackWithMatrix(str0, str1 string, scoring ScoringMatrix, gap float64) [][]string {
+	scoreTable := GlobalScoreTableWithMatrix(str0, str1, scoring, gap)
+	return globalBacktrack(str0, str1, scoreTable, gap)
+}
+
+//globalBacktrack sets backtracking pointers from a filled global alignment score table.
+func globalBacktrack(str0, str1 string, scoreTable [][]float64, gap float64) [][]string {
 	if len(str0) == 0 || len(str1) == 0 {
 		panic("Zero length strings.")
 	}
@@ -63,9 +84,6 @@ func GlobalBacktrack(str0, str1 string, match, mismatch, gap float64) [][]string
 		backtrack[i] = make([]string, numCols)
 	}
 
-	// let's get the scoring matrix values
-	scoreTable := GlobalScoreTable(str0, str1, match, mismatch, gap)
-
 	//first, set backtracking pointers of the 0-th row and column
 	for j := 1; j < numCols; j++ {
 		backtrack[0][j] = "LEFT"
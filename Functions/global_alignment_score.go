@@ -3,6 +3,17 @@ package Functions
 //GlobalScoreTable takes two strings and alignment penalties. It returns a 2-D array
 //holding dynamic programming scores for global alignment with these penalties.
 func GlobalScoreTable(str0, str1 string, match, mismatch, gap float64) [][]float64 {
+	return globalScoreTable(str0, str1, matchMismatch{match, mismatch}, gap)
+}
+
+//GlobalScoreTableWithMatrix takes two strings, a substitution matrix, and a gap penalty.
+//It returns a 2-D array holding dynamic programming scores for global alignment.
+func GlobalScoreTableWithMatrix(str0, str1 string, scoring ScoringMatrix, gap float64) [][]float64 {
+	return globalScoreTable(str0, str1, scoring, gap)
+}
+
+//globalScoreTable fills the global alignment table, scoring diagonal edges with scorer.
+func globalScoreTable(str0, str1 string, scorer symbolScorer, gap float64) [][]float64 {
 	if len(str0) == 0 || len(str1) == 0 {
 		panic("Blah")
 	}
@@ -33,13 +44,7 @@ func GlobalScoreTable(str0, str1 string, match, mismatch, gap float64) [][]float
 			//apply the recurrence relation
 			upValue := scoreTable[i-1][j] - gap   //indel
 			leftValue := scoreTable[i][j-1] - gap //indel
-			var diagonalWeight float64
-			if str0[i-1] == str1[j-1] { //match!
-				diagonalWeight = match
-			} else { // mismatch!
-				diagonalWeight = -mismatch
-			}
-			diagValue := scoreTable[i-1][j-1] + diagonalWeight
+			diagValue := scoreTable[i-1][j-1] + scorer.Score(str0[i-1], str1[j-1])
 			scoreTable[i][j] = MaxFloat(upValue, leftValue, diagValue)
 		}
 	}
@@ -0,0 +1,89 @@
+package Functions
+
+import "sort"
+
+//HammingDistance takes two strings of equal length and returns the number of positions
+//at which their symbols differ.
+func HammingDistance(str1, str2 string) int {
+	if len(str1) != len(str2) {
+		panic("Error: strings given to HammingDistance have different lengths.")
+	}
+
+	count := 0
+	for i := 0; i < len(str1); i++ {
+		if str1[i] != str2[i] {
+			count++
+		}
+	}
+	return count
+}
+
+//ApproximatePatternMatching takes a pattern, a genome, and an integer d. It returns every
+//(0-based) starting position in the genome where the pattern occurs with at most d mismatches,
+//in increasing order.
+func ApproximatePatternMatching(pattern, genome string, d int) []int {
+	positions := make([]int, 0)
+	k := len(pattern)
+	for i := 0; i+k <= len(genome); i++ {
+		if withinMismatches(pattern, genome[i:i+k], d) {
+			positions = append(positions, i)
+		}
+	}
+	return positions
+}
+
+//ApproximatePatternCount returns the number of positions where a pattern occurs in a genome
+//with at most d mismatches.
+func ApproximatePatternCount(pattern, genome string, d int) int {
+	return len(ApproximatePatternMatching(pattern, genome, d))
+}
+
+//withinMismatches returns true if two strings of equal length differ at no more than d
+//positions, stopping as soon as they differ at more.
+func withinMismatches(str1, str2 string, d int) bool {
+	mismatches := 0
+	for i := 0; i < len(str1); i++ {
+		if str1[i] != str2[i] {
+			mismatches++
+			if mismatches > d {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+//Neighbors takes a DNA string and an integer d. It returns the d-neighborhood of the string:
+//every string over A, C, G, and T whose Hamming distance from it is at most d, in
+//alphabetical order. These are the exact seeds that find every match with d mismatches.
+func Neighbors(pattern string, d int) []string {
+	neighborhood := neighbors(pattern, d)
+	sort.Strings(neighborhood)
+	return neighborhood
+}
+
+//neighbors returns the d-neighborhood of a string in no particular order.
+func neighbors(pattern string, d int) []string {
+	if d <= 0 {
+		return []string{pattern}
+	}
+	if len(pattern) == 0 {
+		return []string{""}
+	}
+
+	neighborhood := make([]string, 0)
+	//build the neighbors of the pattern from the neighbors of its suffix
+	suffix := pattern[1:]
+	for _, text := range neighbors(suffix, d) {
+		if HammingDistance(suffix, text) < d {
+			//the first symbol may be anything
+			for _, symbol := range "ACGT" {
+				neighborhood = append(neighborhood, string(symbol)+text)
+			}
+		} else {
+			//all d mismatches are used, so the first symbol must stay the same
+			neighborhood = append(neighborhood, pattern[:1]+text)
+		}
+	}
+	return neighborhood
+}
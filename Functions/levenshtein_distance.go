@@ -2,7 +2,8 @@ package Functions
 
 //EditDistance takes two strings as input. It returns the Levenshtein distance
 //between the two strings; that is, the minimum number of substitutions, insertions, and deletions
-//needed to transform one string into the other.
+//needed to transform one string into the other. Large inputs are handed to
+//BitParallelEditDistance, which gives the same answer without building the whole matrix.
 func EditDistance(str1, str2 string) int {
 	if len(str1) == 0 || len(str2) == 0 {
 		panic("boo")
@@ -10,6 +11,10 @@ func EditDistance(str1, str2 string) int {
 
 	//if we're here, we know that both strings are nonempty
 
+	if len(str1)*len(str2) >= bitParallelThreshold {
+		return BitParallelEditDistance(str1, str2)
+	}
+
 	scoringMatrix := EditMatrix(str1, str2)
 
 	//return the lower right corner
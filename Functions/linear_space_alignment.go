@@ -0,0 +1,108 @@
+package Functions
+
+//GlobalAlignmentLinearSpace takes two strings, along with match, mismatch, and gap scores.
+//It returns a maximum score global alignment of the strings, just like GlobalAlignment, but it
+//uses Hirschberg's divide and conquer approach so that only O(len(str0) + len(str1)) memory
+//is needed. This makes it practical to align whole genomes.
+func GlobalAlignmentLinearSpace(str0, str1 string, match, mismatch, gap float64) Alignment {
+	return hirschberg(str0, str1, matchMismatch{match, mismatch}, gap)
+}
+
+//GlobalAlignmentLinearSpaceWithMatrix is the substitution matrix analogue of
+//GlobalAlignmentLinearSpace.
+func GlobalAlignmentLinearSpaceWithMatrix(str0, str1 string, scoring ScoringMatrix, gap float64) Alignment {
+	return hirschberg(str0, str1, scoring, gap)
+}
+
+//hirschberg finds the column where an optimal alignment crosses the middle row of the
+//alignment graph, then aligns the two halves recursively.
+func hirschberg(str0, str1 string, scorer symbolScorer, gap float64) Alignment {
+	var a Alignment
+
+	//base cases: one of the strings is empty, so the alignment is all gaps
+	if len(str0) == 0 {
+		a[0] = gapString(len(str1))
+		a[1] = str1
+		return a
+	}
+	if len(str1) == 0 {
+		a[0] = str0
+		a[1] = gapString(len(str0))
+		return a
+	}
+
+	//a single row is cheap to align with the quadratic algorithm
+	if len(str0) == 1 {
+		scoreTable := globalScoreTable(str0, str1, scorer, gap)
+		return OutputGlobalAlignment(str0, str1, globalBacktrack(str0, str1, scoreTable, gap))
+	}
+
+	midRow := len(str0) / 2
+
+	//scores from the source to the middle row, and from the middle row to the sink
+	//(computed by aligning the reversed suffixes)
+	fromSource := lastScoreRow(str0[:midRow], str1, scorer, gap)
+	toSink := lastScoreRow(reverseString(str0[midRow:]), reverseString(str1), scorer, gap)
+
+	//the middle node maximizes the length of a path through it
+	midCol := 0
+	n := len(str1)
+	for j := 0; j <= n; j++ {
+		if fromSource[j]+toSink[n-j] > fromSource[midCol]+toSink[n-midCol] {
+			midCol = j
+		}
+	}
+
+	top := hirschberg(str0[:midRow], str1[:midCol], scorer, gap)
+	bottom := hirschberg(str0[midRow:], str1[midCol:], scorer, gap)
+
+	a[0] = top[0] + bottom[0]
+	a[1] = top[1] + bottom[1]
+	return a
+}
+
+//lastScoreRow takes two strings, a scorer, and a gap penalty. It returns the last row of the
+//global alignment score table of the strings, keeping only two rows in memory at a time.
+//The string str0 may be empty, in which case the 0-th row is returned.
+func lastScoreRow(str0, str1 string, scorer symbolScorer, gap float64) []float64 {
+	numCols := len(str1) + 1
+
+	prevRow := make([]float64, numCols)
+	currRow := make([]float64, numCols)
+
+	//0-th row is all gaps
+	for j := 1; j < numCols; j++ {
+		prevRow[j] = float64(j) * (-gap)
+	}
+
+	for i := 1; i <= len(str0); i++ {
+		currRow[0] = float64(i) * (-gap)
+		for j := 1; j < numCols; j++ {
+			upValue := prevRow[j] - gap
+			leftValue := currRow[j-1] - gap
+			diagValue := prevRow[j-1] + scorer.Score(str0[i-1], str1[j-1])
+			currRow[j] = MaxFloat(upValue, leftValue, diagValue)
+		}
+		prevRow, currRow = currRow, prevRow
+	}
+
+	return prevRow
+}
+
+//reverseString returns the symbols of a string in reverse order.
+func reverseString(s string) string {
+	b := make([]byte, len(s))
+	for i := range s {
+		b[len(s)-1-i] = s[i]
+	}
+	return string(b)
+}
+
+//gapString returns a string of n gap symbols.
+func gapString(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = '-'
+	}
+	return string(b)
+}
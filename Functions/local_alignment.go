@@ -6,7 +6,116 @@ package Functions
 //alignment is of [start0: end0] and [start1: end1] in the respective strings.
 //(That is, the strings don't include the symbols at positions end0 or end1, respectively.)
 func LocalAlignment(str0, str1 string, match, mismatch, gap float64) (Alignment, int, int, int, int) {
-	//replace this with your code
+	return localAlignment(str0, str1, matchMismatch{match, mismatch}, gap)
+}
+
+//LocalAlignmentWithMatrix takes two strings, a substitution matrix such as BLOSUM62, and a gap
+//penalty. It returns a maximum score local alignment along with start0, end0, start1, and end1
+//as in LocalAlignment.
+func LocalAlignmentWithMatrix(str0, str1 string, scoring ScoringMatrix, gap float64) (Alignment, int, int, int, int) {
+	return localAlignment(str0, str1, scoring, gap)
+}
+
+//localAlignment finds a maximum score local alignment, scoring diagonal edges with scorer.
+func localAlignment(str0, str1 string, scorer symbolScorer, gap float64) (Alignment, int, int, int, int) {
+	scoreTable := localScoreTable(str0, str1, scorer, gap)
+
+	//the alignment ends at the largest value in the table (a free ride to the sink)
+	end0, end1 := MaxTableCell(scoreTable)
+
+	backtrack := localBacktrack(str0, str1, scoreTable, scorer, gap)
+	optAlignment, start0, start1 := OutputLocalAlignment(str0[:end0], str1[:end1], backtrack)
+
+	return optAlignment, start0, end0, start1, end1
+}
+
+//OutputLocalAlignment takes two strings that have been trimmed to end at the sink of
+//a local alignment, along with a matrix of local backtracking pointers.
+//It returns the alignment along with the positions in each string where it starts.
+func OutputLocalAlignment(str0, str1 string, backtrack [][]string) (Alignment, int, int) {
 	var a Alignment
-	return a, 0, 0, 0, 0
+
+	//walk backward until we hit a free ride from the source
+	for {
+		row := len(str0)
+		col := len(str1)
+
+		if backtrack[row][col] == "FREE" {
+			return a, row, col
+		} else if backtrack[row][col] == "UP" {
+			a[0] = string(str0[row-1]) + a[0]
+			a[1] = "-" + a[1]
+			str0 = str0[:len(str0)-1]
+		} else if backtrack[row][col] == "LEFT" {
+			a[0] = "-" + a[0]
+			a[1] = string(str1[col-1]) + a[1]
+			str1 = str1[:len(str1)-1]
+		} else if backtrack[row][col] == "DIAG" {
+			a[0] = string(str0[row-1]) + a[0]
+			a[1] = string(str1[col-1]) + a[1]
+			str0 = str0[:len(str0)-1]
+			str1 = str1[:len(str1)-1]
+		} else {
+			panic("Illegal backtracking pointer.")
+		}
+	}
+}
+
+//LocalBacktrack takes two strings, their local alignment score table, and alignment penalties.
+//It returns a 2-D slice of backtracking pointers ("UP", "LEFT", "DIAG", "FREE"), where "FREE"
+//marks a node reached by a free ride from the source.
+func LocalBacktrack(str0, str1 string, scoreTable [][]float64, match, mismatch, gap float64) [][]string {
+	return localBacktrack(str0, str1, scoreTable, matchMismatch{match, mismatch}, gap)
+}
+
+//LocalBacktrackWithMatrix is the substitution matrix analogue of LocalBacktrack.
+func LocalBacktrackWithMatrix(str0, str1 string, scoreTable [][]float64, scoring ScoringMatrix, gap float64) [][]string {
+	return localBacktrack(str0, str1, scoreTable, scoring, gap)
+}
+
+//localBacktrack sets local backtracking pointers, scoring diagonal edges with scorer.
+func localBacktrack(str0, str1 string, scoreTable [][]float64, scorer symbolScorer, gap float64) [][]string {
+	numRows := len(str0) + 1
+	numCols := len(str1) + 1
+
+	backtrack := make([][]string, numRows)
+	for i := range backtrack {
+		backtrack[i] = make([]string, numCols)
+	}
+
+	for i := 0; i < numRows; i++ {
+		for j := 0; j < numCols; j++ {
+			if i == 0 || j == 0 || scoreTable[i][j] == 0.0 {
+				//nothing to gain by going back any further
+				backtrack[i][j] = "FREE"
+				continue
+			}
+
+			if scoreTable[i][j] == scoreTable[i-1][j-1]+scorer.Score(str0[i-1], str1[j-1]) {
+				backtrack[i][j] = "DIAG"
+			} else if scoreTable[i][j] == scoreTable[i-1][j]-gap {
+				backtrack[i][j] = "UP"
+			} else if scoreTable[i][j] == scoreTable[i][j-1]-gap {
+				backtrack[i][j] = "LEFT"
+			} else {
+				panic("Error: score table value not reached by any edge.")
+			}
+		}
+	}
+
+	return backtrack
+}
+
+//MaxTableCell takes a 2-D table of scores and returns the row and column
+//of a largest value in the table.
+func MaxTableCell(scoreTable [][]float64) (int, int) {
+	maxRow, maxCol := 0, 0
+	for i := range scoreTable {
+		for j := range scoreTable[i] {
+			if scoreTable[i][j] > scoreTable[maxRow][maxCol] {
+				maxRow, maxCol = i, j
+			}
+		}
+	}
+	return maxRow, maxCol
 }
@@ -3,6 +3,41 @@ package Functions
 //LocalScoreTable takes two strings and alignment penalties. It returns a 2-D array
 //holding dynamic programming scores for local alignment with these penalties.
 func LocalScoreTable(str0, str1 string, match, mismatch, gap float64) [][]float64 {
-	//replace this with your code
-	return [][]float64{}
+	return localScoreTable(str0, str1, matchMismatch{match, mismatch}, gap)
+}
+
+//LocalScoreTableWithMatrix takes two strings, a substitution matrix, and a gap penalty.
+//It returns a 2-D array holding dynamic programming scores for local alignment.
+func LocalScoreTableWithMatrix(str0, str1 string, scoring ScoringMatrix, gap float64) [][]float64 {
+	return localScoreTable(str0, str1, scoring, gap)
+}
+
+//localScoreTable fills the local alignment table, scoring diagonal edges with scorer.
+func localScoreTable(str0, str1 string, scorer symbolScorer, gap float64) [][]float64 {
+	if len(str0) == 0 || len(str1) == 0 {
+		panic("Zero length strings.")
+	}
+
+	numRows := len(str0) + 1
+	numCols := len(str1) + 1
+
+	scoreTable := make([][]float64, numRows)
+	for i := range scoreTable {
+		scoreTable[i] = make([]float64, numCols)
+	}
+
+	//the 0-th row and column stay at zero, since we can take a free ride
+	//from the source to any node
+
+	for i := 1; i < numRows; i++ {
+		for j := 1; j < numCols; j++ {
+			upValue := scoreTable[i-1][j] - gap   //indel
+			leftValue := scoreTable[i][j-1] - gap //indel
+			diagValue := scoreTable[i-1][j-1] + scorer.Score(str0[i-1], str1[j-1])
+			//the zero value corresponds to a free ride from the source
+			scoreTable[i][j] = MaxFloat(0.0, upValue, leftValue, diagValue)
+		}
+	}
+
+	return scoreTable
 }
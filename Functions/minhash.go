@@ -0,0 +1,256 @@
+package Functions
+
+import (
+	"bufio"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//Sketch is a bottom-k MinHash sketch of the set of k-mers of a sequence: the Size smallest
+//64-bit hashes of its distinct k-mers, in increasing order. Two sketches made with the same K
+//estimate the Jaccard similarity of the k-mer sets they summarize.
+type Sketch struct {
+	Name   string
+	K      int
+	Size   int
+	Hashes []uint64
+}
+
+//hashKmer returns the 64-bit hash of a k-mer used in sketches: FNV-1a followed by the
+//MurmurHash3 finalizer, which spreads the bits so that the smallest hashes are a fair sample.
+//Sketches written to disk depend on this function staying the same.
+func hashKmer(kmer string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(kmer))
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
+
+//SketchFrequencyMap takes a name, a frequency map of k-mers such as the one returned by
+//FrequencyMap, the k-mer length, and a sketch size. It returns the bottom-k sketch of the
+//k-mers in the map; counts are ignored, since a sketch summarizes a set.
+func SketchFrequencyMap(name string, freq map[string]int, k, size int) Sketch {
+	if size <= 0 {
+		panic("Error: sketch size must be positive.")
+	}
+
+	hashes := make([]uint64, 0, len(freq))
+	for kmer := range freq {
+		hashes = append(hashes, hashKmer(kmer))
+	}
+	sort.Slice(hashes, func(i, j int) bool { return hashes[i] < hashes[j] })
+
+	//drop repeated hashes, which only collisions could produce, and keep the smallest
+	distinct := hashes[:0]
+	for i, h := range hashes {
+		if i == 0 || h != hashes[i-1] {
+			distinct = append(distinct, h)
+		}
+	}
+	if len(distinct) > size {
+		distinct = distinct[:size]
+	}
+
+	return Sketch{Name: name, K: k, Size: size, Hashes: append([]uint64(nil), distinct...)}
+}
+
+//SketchSequence takes a name, a DNA sequence, the k-mer length, and a sketch size. It returns
+//the bottom-k sketch of the sequence's canonical k-mers, so that sequences taken from opposite
+//strands are recognized as similar, as the Mash tool does. Symbols are uppercased and U is read
+//as T. Like Mash, it skips k-mers holding N or any other symbol besides A, C, G, and T, since
+//runs of N in unrelated assemblies would otherwise make them look alike.
+func SketchSequence(name, text string, k, size int) Sketch {
+	text = strings.Replace(strings.ToUpper(text), "U", "T", -1)
+	freq := make(map[string]int)
+
+	//lastInvalid is the index of the most recent symbol that isn't a nucleotide
+	lastInvalid := -1
+	for i := 0; i < len(text); i++ {
+		if packedBase(text[i]) < 0 {
+			lastInvalid = i
+		}
+		start := i - k + 1
+		if start >= 0 && lastInvalid < start {
+			freq[CanonicalKmer(text[start:i+1])]++
+		}
+	}
+
+	return SketchFrequencyMap(name, freq, k, size)
+}
+
+//JaccardEstimate takes two sketches made with the same k-mer length and returns an estimate of
+//the Jaccard similarity of their k-mer sets: the fraction of the smallest hashes of the union of
+//the sketches that are in both, using the smaller of the two sketch sizes.
+func JaccardEstimate(s1, s2 Sketch) float64 {
+	if s1.K != s2.K {
+		panic("Error: cannot compare sketches made with different k-mer lengths.")
+	}
+	size := Min2(s1.Size, s2.Size)
+
+	//walk both sorted lists in step, taking the smallest hashes of the union
+	shared, union := 0, 0
+	i, j := 0, 0
+	for union < size && (i < len(s1.Hashes) || j < len(s2.Hashes)) {
+		if j == len(s2.Hashes) || (i < len(s1.Hashes) && s1.Hashes[i] < s2.Hashes[j]) {
+			i++
+		} else if i == len(s1.Hashes) || s2.Hashes[j] < s1.Hashes[i] {
+			j++
+		} else {
+			shared++
+			i++
+			j++
+		}
+		union++
+	}
+
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
+
+//MashDistance takes two sketches made with the same k-mer length and returns the Mash distance
+//between them, -ln(2j / (1 + j)) / k for the Jaccard estimate j. It estimates the proportion of
+//sites that differ between the sequences, and is 1 if the sketches share no hashes.
+func MashDistance(s1, s2 Sketch) float64 {
+	j := JaccardEstimate(s1, s2)
+	if j == 0 {
+		return 1
+	}
+	return math.Log((1+j)/(2*j)) / float64(s1.K)
+}
+
+//JaccardIndex takes two frequency maps of k-mers and returns the exact Jaccard similarity of
+//their sets of k-mers: the number in both divided by the number in either.
+func JaccardIndex(freqMap1, freqMap2 map[string]int) float64 {
+	shared := 0
+	for kmer := range freqMap1 {
+		if _, ok := freqMap2[kmer]; ok {
+			shared++
+		}
+	}
+	union := len(freqMap1) + len(freqMap2) - shared
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
+
+//sketchFileHeader begins every sketch file and names the hash function its hashes came from.
+const sketchFileHeader = "#minhash-sketches v1 fnv1a64-fmix64"
+
+//WriteSketches writes sketches in a text format read by ReadSketches. After a header line, each
+//sketch is one line holding its tab-separated name, k-mer length, size, and hashes, with the
+//hashes written in hexadecimal and separated by commas.
+func WriteSketches(w io.Writer, sketches []Sketch) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, sketchFileHeader)
+	for _, s := range sketches {
+		if strings.ContainsAny(s.Name, "\t\n") {
+			return fmt.Errorf("sketch name %q holds a tab or newline", s.Name)
+		}
+		hexHashes := make([]string, len(s.Hashes))
+		for i, h := range s.Hashes {
+			hexHashes[i] = strconv.FormatUint(h, 16)
+		}
+		fmt.Fprintf(bw, "%s\t%d\t%d\t%s\n", s.Name, s.K, s.Size, strings.Join(hexHashes, ","))
+	}
+	return bw.Flush()
+}
+
+//WriteSketchFile writes sketches to a file in the format of WriteSketches.
+func WriteSketchFile(filename string, sketches []Sketch) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := WriteSketches(file, sketches); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+//ReadSketches reads sketches written by WriteSketches.
+func ReadSketches(r io.Reader) ([]Sketch, error) {
+	scanner := bufio.NewScanner(r)
+	//a sketch of many hashes makes a long line
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("empty sketch file")
+	}
+	if scanner.Text() != sketchFileHeader {
+		return nil, fmt.Errorf("line 1: expected %q, got %q", sketchFileHeader, scanner.Text())
+	}
+
+	sketches := make([]Sketch, 0)
+	lineNumber := 1
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("line %d: expected 4 tab-separated fields, got %d", lineNumber, len(fields))
+		}
+		s := Sketch{Name: fields[0], Hashes: make([]uint64, 0)}
+		var err error
+		if s.K, err = strconv.Atoi(fields[1]); err != nil || s.K <= 0 {
+			return nil, fmt.Errorf("line %d: invalid k-mer length %q", lineNumber, fields[1])
+		}
+		if s.Size, err = strconv.Atoi(fields[2]); err != nil || s.Size <= 0 {
+			return nil, fmt.Errorf("line %d: invalid sketch size %q", lineNumber, fields[2])
+		}
+		if fields[3] != "" {
+			for _, field := range strings.Split(fields[3], ",") {
+				h, err := strconv.ParseUint(field, 16, 64)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: invalid hash %q", lineNumber, field)
+				}
+				if len(s.Hashes) > 0 && h <= s.Hashes[len(s.Hashes)-1] {
+					return nil, fmt.Errorf("line %d: hashes are not in increasing order", lineNumber)
+				}
+				s.Hashes = append(s.Hashes, h)
+			}
+		}
+		if len(s.Hashes) > s.Size {
+			return nil, fmt.Errorf("line %d: %d hashes in a sketch of size %d", lineNumber, len(s.Hashes), s.Size)
+		}
+		sketches = append(sketches, s)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return sketches, nil
+}
+
+//ReadSketchFile reads sketches from a file written by WriteSketchFile.
+func ReadSketchFile(filename string) ([]Sketch, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	sketches, err := ReadSketches(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return sketches, nil
+}
@@ -0,0 +1,211 @@
+package Functions
+
+//MultipleAlignment generalizes Alignment to any number of rows. Every row has the same length,
+//and row i holds the i-th aligned string with "-" marking gaps.
+type MultipleAlignment []string
+
+//NumRows returns the number of aligned strings.
+func (ma MultipleAlignment) NumRows() int {
+	return len(ma)
+}
+
+//NumColumns returns the length of the alignment.
+func (ma MultipleAlignment) NumColumns() int {
+	if len(ma) == 0 {
+		return 0
+	}
+	return len(ma[0])
+}
+
+//Pair returns the pairwise alignment induced by rows i and j, dropping the columns in which
+//both rows hold a gap.
+func (ma MultipleAlignment) Pair(i, j int) Alignment {
+	var top, bottom []byte
+	for col := 0; col < ma.NumColumns(); col++ {
+		if ma[i][col] == '-' && ma[j][col] == '-' {
+			continue
+		}
+		top = append(top, ma[i][col])
+		bottom = append(bottom, ma[j][col])
+	}
+	return Alignment{string(top), string(bottom)}
+}
+
+//SumOfPairsScore takes a multiple alignment along with match, mismatch, and gap scores.
+//It returns the sum of the scores of the pairwise alignments induced by every pair of rows.
+func SumOfPairsScore(ma MultipleAlignment, match, mismatch, gap float64) float64 {
+	return sumOfPairsScore(ma, matchMismatch{match, mismatch}, gap)
+}
+
+//SumOfPairsScoreWithMatrix is like SumOfPairsScore, but scores symbols with a substitution matrix.
+func SumOfPairsScoreWithMatrix(ma MultipleAlignment, scoring ScoringMatrix, gap float64) float64 {
+	return sumOfPairsScore(ma, scoring, gap)
+}
+
+//sumOfPairsScore holds the scoring-independent part of SumOfPairsScore.
+func sumOfPairsScore(ma MultipleAlignment, scorer symbolScorer, gap float64) float64 {
+	score := 0.0
+	for i := 0; i < ma.NumRows(); i++ {
+		for j := i + 1; j < ma.NumRows(); j++ {
+			pair := ma.Pair(i, j)
+			for col := range pair[0] {
+				if pair[0][col] == '-' || pair[1][col] == '-' {
+					score -= gap
+				} else {
+					score += scorer.Score(pair[0][col], pair[1][col])
+				}
+			}
+		}
+	}
+	return score
+}
+
+//AlignProfiles takes two multiple alignments (profiles) along with match, mismatch, and gap
+//scores. It returns a maximum score alignment of the profiles, holding the rows of profile0
+//followed by the rows of profile1. Columns of each profile are kept together, and a column is
+//scored against another by the average score over all pairs of their symbols, where a symbol
+//against a gap costs the gap penalty and a gap against a gap costs nothing.
+func AlignProfiles(profile0, profile1 MultipleAlignment, match, mismatch, gap float64) MultipleAlignment {
+	return alignProfiles(profile0, profile1, matchMismatch{match, mismatch}, gap)
+}
+
+//AlignProfilesWithMatrix is like AlignProfiles, but scores symbols with a substitution matrix.
+func AlignProfilesWithMatrix(profile0, profile1 MultipleAlignment, scoring ScoringMatrix, gap float64) MultipleAlignment {
+	return alignProfiles(profile0, profile1, scoring, gap)
+}
+
+//alignProfiles holds the scoring-independent part of AlignProfiles.
+func alignProfiles(profile0, profile1 MultipleAlignment, scorer symbolScorer, gap float64) MultipleAlignment {
+	if profile0.NumRows() == 0 || profile1.NumRows() == 0 {
+		panic("Error: cannot align an empty profile.")
+	}
+
+	columns0 := profileColumns(profile0)
+	columns1 := profileColumns(profile1)
+	rows0 := float64(profile0.NumRows())
+	rows1 := float64(profile1.NumRows())
+
+	numRows := len(columns0) + 1
+	numCols := len(columns1) + 1
+	scoreTable := InitializeFloatTable(numRows, numCols)
+	backtrack := InitializeStringTable(numRows, numCols)
+
+	//aligning a column against a column of gaps costs the gap penalty for each of its symbols
+	gapCost0 := make([]float64, len(columns0))
+	for i, column := range columns0 {
+		gapCost0[i] = gap * (rows0 - float64(column['-'])) / rows0
+	}
+	gapCost1 := make([]float64, len(columns1))
+	for j, column := range columns1 {
+		gapCost1[j] = gap * (rows1 - float64(column['-'])) / rows1
+	}
+
+	for i := 1; i < numRows; i++ {
+		scoreTable[i][0] = scoreTable[i-1][0] - gapCost0[i-1]
+		backtrack[i][0] = "UP"
+	}
+	for j := 1; j < numCols; j++ {
+		scoreTable[0][j] = scoreTable[0][j-1] - gapCost1[j-1]
+		backtrack[0][j] = "LEFT"
+	}
+
+	for i := 1; i < numRows; i++ {
+		for j := 1; j < numCols; j++ {
+			diag := scoreTable[i-1][j-1] + profileColumnScore(columns0[i-1], columns1[j-1], scorer, gap)/(rows0*rows1)
+			up := scoreTable[i-1][j] - gapCost0[i-1]
+			left := scoreTable[i][j-1] - gapCost1[j-1]
+
+			//prefer matching columns, then gaps in profile1, then gaps in profile0
+			scoreTable[i][j] = diag
+			backtrack[i][j] = "DIAG"
+			if up > scoreTable[i][j] {
+				scoreTable[i][j] = up
+				backtrack[i][j] = "UP"
+			}
+			if left > scoreTable[i][j] {
+				scoreTable[i][j] = left
+				backtrack[i][j] = "LEFT"
+			}
+		}
+	}
+
+	return outputProfileAlignment(profile0, profile1, backtrack)
+}
+
+//profileColumns counts the symbols (including "-") in each column of a profile.
+func profileColumns(profile MultipleAlignment) []map[byte]int {
+	columns := make([]map[byte]int, profile.NumColumns())
+	for col := range columns {
+		columns[col] = make(map[byte]int)
+		for _, row := range profile {
+			columns[col][row[col]]++
+		}
+	}
+	return columns
+}
+
+//profileColumnScore returns the total score of all pairs of symbols taken one from each column.
+func profileColumnScore(column0, column1 map[byte]int, scorer symbolScorer, gap float64) float64 {
+	score := 0.0
+	for a, count0 := range column0 {
+		for b, count1 := range column1 {
+			pairs := float64(count0 * count1)
+			if a == '-' && b == '-' {
+				continue
+			} else if a == '-' || b == '-' {
+				score -= gap * pairs
+			} else {
+				score += scorer.Score(a, b) * pairs
+			}
+		}
+	}
+	return score
+}
+
+//outputProfileAlignment follows backtracking pointers from the bottom right of the table and
+//returns the rows of both profiles with gap columns inserted.
+func outputProfileAlignment(profile0, profile1 MultipleAlignment, backtrack [][]string) MultipleAlignment {
+	rows := make([][]byte, profile0.NumRows()+profile1.NumRows())
+	offset := profile0.NumRows()
+
+	row := profile0.NumColumns()
+	col := profile1.NumColumns()
+	for row > 0 || col > 0 {
+		//symbols are appended backward and reversed at the end
+		switch backtrack[row][col] {
+		case "DIAG":
+			row--
+			col--
+			for r := range profile0 {
+				rows[r] = append(rows[r], profile0[r][row])
+			}
+			for r := range profile1 {
+				rows[offset+r] = append(rows[offset+r], profile1[r][col])
+			}
+		case "UP":
+			row--
+			for r := range profile0 {
+				rows[r] = append(rows[r], profile0[r][row])
+			}
+			for r := range profile1 {
+				rows[offset+r] = append(rows[offset+r], '-')
+			}
+		case "LEFT":
+			col--
+			for r := range profile0 {
+				rows[r] = append(rows[r], '-')
+			}
+			for r := range profile1 {
+				rows[offset+r] = append(rows[offset+r], profile1[r][col])
+			}
+		default:
+			panic("Illegal backtracking pointer.")
+		}
+	}
+
+	ma := make(MultipleAlignment, len(rows))
+	for r := range rows {
+		ma[r] = reverseString(string(rows[r]))
+	}
+	return ma
+}
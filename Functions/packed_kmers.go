@@ -0,0 +1,169 @@
+package Functions
+
+//MaxPackedK is the longest k-mer that fits in a uint64 at two bits per nucleotide.
+const MaxPackedK = 32
+
+//packedBase returns the two-bit code of a nucleotide (A = 0, C = 1, G = 2, T = 3, so that
+//codes sort like k-mers), ignoring case and reading U as T, or -1 for any other symbol.
+func packedBase(c byte) int {
+	switch c {
+	case 'A', 'a':
+		return 0
+	case 'C', 'c':
+		return 1
+	case 'G', 'g':
+		return 2
+	case 'T', 't', 'U', 'u':
+		return 3
+	}
+	return -1
+}
+
+//checkPackedK panics if k-mers of length k can't be packed into a uint64.
+func checkPackedK(k int) {
+	if k <= 0 || k > MaxPackedK {
+		panic("Error: packed k-mers must have a length between 1 and 32.")
+	}
+}
+
+//EncodeKmer packs a DNA k-mer of length at most 32 into a uint64, two bits per nucleotide with
+//the first nucleotide in the highest bits. It returns false if the k-mer holds any symbol other
+//than A, C, G, T, or U (in either case).
+func EncodeKmer(kmer string) (uint64, bool) {
+	checkPackedK(len(kmer))
+	var code uint64
+	for i := 0; i < len(kmer); i++ {
+		b := packedBase(kmer[i])
+		if b < 0 {
+			return 0, false
+		}
+		code = code<<2 | uint64(b)
+	}
+	return code, true
+}
+
+//DecodeKmer takes a packed k-mer and its length and returns it as an uppercase DNA string.
+func DecodeKmer(code uint64, k int) string {
+	checkPackedK(k)
+	b := make([]byte, k)
+	for i := k - 1; i >= 0; i-- {
+		b[i] = "ACGT"[code&3]
+		code >>= 2
+	}
+	return string(b)
+}
+
+//PackedFrequencyMap is like FrequencyMap for DNA, but keys each k-mer by its packed code,
+//found by rolling the code of the previous k-mer forward one symbol, so no substrings are made.
+//Symbols are read ignoring case, and k-mers holding any symbol other than A, C, G, T, or U are
+//skipped. k must be between 1 and 32.
+func PackedFrequencyMap(text string, k int) map[uint64]int {
+	checkPackedK(k)
+	freq := make(map[uint64]int, expectedKmers(len(text), k))
+	mask := packedMask(k)
+
+	var code uint64
+	valid := 0 // number of valid symbols ending at the current position, up to k
+	for i := 0; i < len(text); i++ {
+		b := packedBase(text[i])
+		if b < 0 {
+			//no k-mer overlapping this symbol is counted
+			valid = 0
+			continue
+		}
+		code = (code<<2 | uint64(b)) & mask
+		if valid < k {
+			valid++
+		}
+		if valid == k {
+			freq[code]++
+		}
+	}
+	return freq
+}
+
+//CanonicalPackedFrequencyMap is like PackedFrequencyMap, but counts each k-mer under the code of
+//its canonical k-mer, rolling the reverse complement forward alongside the k-mer.
+func CanonicalPackedFrequencyMap(text string, k int) map[uint64]int {
+	checkPackedK(k)
+	freq := make(map[uint64]int, expectedKmers(len(text), k))
+	mask := packedMask(k)
+	shift := uint(2 * (k - 1))
+
+	var code, rc uint64
+	valid := 0
+	for i := 0; i < len(text); i++ {
+		b := packedBase(text[i])
+		if b < 0 {
+			valid = 0
+			continue
+		}
+		code = (code<<2 | uint64(b)) & mask
+		//the complement of a base code is 3 minus it, and it enters at the front of rc
+		rc = rc>>2 | uint64(3-b)<<shift
+		if valid < k {
+			valid++
+		}
+		if valid == k {
+			if rc < code {
+				freq[rc]++
+			} else {
+				freq[code]++
+			}
+		}
+	}
+	return freq
+}
+
+//expectedKmers returns the number of k-mers of a text of length n, which sizes a frequency
+//map up front instead of growing it while counting; a genome has few repeated k-mers for large k.
+func expectedKmers(n, k int) int {
+	if n < k {
+		return 0
+	}
+	if k < MaxPackedK/2 && n-k+1 > 1<<uint(2*k) {
+		//there can't be more distinct k-mers than 4^k
+		return 1 << uint(2*k)
+	}
+	return n - k + 1
+}
+
+//packedMask returns a mask keeping the low 2k bits of a packed code.
+func packedMask(k int) uint64 {
+	if k == MaxPackedK {
+		return ^uint64(0)
+	}
+	return uint64(1)<<uint(2*k) - 1
+}
+
+//DecodeFrequencyMap converts a packed frequency map of k-mers into the map[string]int
+//returned by FrequencyMap.
+func DecodeFrequencyMap(freq map[uint64]int, k int) map[string]int {
+	decoded := make(map[string]int, len(freq))
+	for code, count := range freq {
+		decoded[DecodeKmer(code, k)] = count
+	}
+	return decoded
+}
+
+//CountSharedKmersPacked is like CountSharedKmers for DNA, but counts k-mers with
+//PackedFrequencyMap. It agrees with CountSharedKmers on uppercase strings of A, C, G, and T.
+func CountSharedKmersPacked(str1, str2 string, k int) int {
+	return countSharedPacked(PackedFrequencyMap(str1, k), PackedFrequencyMap(str2, k))
+}
+
+//CountSharedCanonicalKmersPacked is like CountSharedCanonicalKmers, but counts k-mers with
+//CanonicalPackedFrequencyMap.
+func CountSharedCanonicalKmersPacked(str1, str2 string, k int) int {
+	return countSharedPacked(CanonicalPackedFrequencyMap(str1, k), CanonicalPackedFrequencyMap(str2, k))
+}
+
+//countSharedPacked sums the smaller count of each k-mer in two packed frequency maps.
+func countSharedPacked(freqMap1, freqMap2 map[uint64]int) int {
+	count := 0
+	for code, count1 := range freqMap1 {
+		count += Min2(count1, freqMap2[code])
+	}
+	return count
+}
+
@@ -0,0 +1,165 @@
+package Functions
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+//MatrixOptions controls the concurrent computation of a pairwise distance matrix.
+//Workers is the number of goroutines computing distances; zero or less means one per CPU.
+//If Progress isn't nil, it is called with the number of finished pairs and the total after
+//each pair. Progress is always called from the goroutine that requested the matrix.
+type MatrixOptions struct {
+	Workers  int
+	Progress func(done, total int)
+}
+
+//EditDistanceMatrixParallel is like EditDistanceMatrix, but computes the edit distances
+//concurrently. It returns the same matrix as EditDistanceMatrix, or the context's error if
+//the context is canceled before every distance has been computed.
+func EditDistanceMatrixParallel(ctx context.Context, patterns []string, opts MatrixOptions) ([][]int, error) {
+	mtx := make([][]int, len(patterns))
+	for i := range mtx {
+		mtx[i] = make([]int, len(patterns))
+	}
+
+	err := computePairs(ctx, len(patterns), opts, func(i, j int) error {
+		d := EditDistance(patterns[i], patterns[j])
+		mtx[i][j] = d
+		mtx[j][i] = d
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mtx, nil
+}
+
+//ModelDistanceMatrixParallel is like ModelDistanceMatrix, but aligns the pairs of strings
+//concurrently. It returns the context's error if the context is canceled first, or the error
+//for the first pair whose distance the model can't estimate.
+func ModelDistanceMatrixParallel(ctx context.Context, patterns []string, model DistanceModel, match, mismatch, gap float64, opts MatrixOptions) (DistanceMatrix, error) {
+	return modelDistanceMatrixParallel(ctx, patterns, model, func(str0, str1 string) Alignment {
+		return GlobalAlignmentLinearSpace(str0, str1, match, mismatch, gap)
+	}, opts)
+}
+
+//ModelDistanceMatrixParallelWithMatrix is like ModelDistanceMatrixParallel, but aligns the
+//strings using a substitution matrix such as BLOSUM62.
+func ModelDistanceMatrixParallelWithMatrix(ctx context.Context, patterns []string, model DistanceModel, scoring ScoringMatrix, gap float64, opts MatrixOptions) (DistanceMatrix, error) {
+	return modelDistanceMatrixParallel(ctx, patterns, model, func(str0, str1 string) Alignment {
+		return GlobalAlignmentLinearSpaceWithMatrix(str0, str1, scoring, gap)
+	}, opts)
+}
+
+//modelDistanceMatrixParallel fills a distance matrix concurrently using a function that
+//aligns two strings.
+func modelDistanceMatrixParallel(ctx context.Context, patterns []string, model DistanceModel, align func(string, string) Alignment, opts MatrixOptions) (DistanceMatrix, error) {
+	mtx := make(DistanceMatrix, len(patterns))
+	for i := range mtx {
+		mtx[i] = make([]float64, len(patterns))
+	}
+
+	err := computePairs(ctx, len(patterns), opts, func(i, j int) error {
+		d, err := model.Distance(align(patterns[i], patterns[j]))
+		if err != nil {
+			return pairError(i, j, err)
+		}
+		mtx[i][j] = d
+		mtx[j][i] = d
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mtx, nil
+}
+
+//computePairs calls compute(i, j) for every 0 <= i < j < n using a pool of workers. Each call
+//must only write to cells that belong to its own pair, so the result doesn't depend on the
+//order in which the pairs finish. computePairs stops handing out pairs and returns the
+//context's error as soon as the context is canceled, or the error of the first call to
+//compute that fails.
+func computePairs(ctx context.Context, n int, opts MatrixOptions, compute func(i, j int) error) error {
+	total := n * (n - 1) / 2
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if total <= 0 {
+		return nil
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > total {
+		workers = total
+	}
+
+	//ctx is canceled when we return, which stops the feeder and workers if we return early
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pairs := make(chan [2]int)
+	done := make(chan struct{}, workers)
+	//each worker sends at most one error, so this never blocks
+	errs := make(chan error, workers)
+
+	//the feeder hands out pairs in row order
+	go func() {
+		defer close(pairs)
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				select {
+				case pairs <- [2]int{i, j}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for pair := range pairs {
+				if err := compute(pair[0], pair[1]); err != nil {
+					errs <- err
+					return
+				}
+				select {
+				case done <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	//collect one signal per finished pair
+	for finished := 0; finished < total; {
+		select {
+		case <-done:
+			finished++
+			if opts.Progress != nil {
+				opts.Progress(finished, total)
+			}
+		case err := <-errs:
+			cancel()
+			wg.Wait()
+			return err
+		case <-ctx.Done():
+			//wait for workers to finish their current pair so nothing writes after we return
+			cancel()
+			wg.Wait()
+			return ctx.Err()
+		}
+	}
+
+	wg.Wait()
+	return nil
+}
@@ -0,0 +1,47 @@
+package Functions
+
+//ProgressiveAlignment takes a slice of strings along with match, mismatch, and gap scores.
+//It builds a UPGMA guide tree from the edit distances between the strings, then aligns them
+//profile against profile from the leaves of the tree to the root. The rows of the returned
+//alignment are in the same order as the input strings.
+func ProgressiveAlignment(patterns []string, match, mismatch, gap float64) MultipleAlignment {
+	return progressiveAlignment(patterns, matchMismatch{match, mismatch}, gap)
+}
+
+//ProgressiveAlignmentWithMatrix is like ProgressiveAlignment, but scores symbols with a
+//substitution matrix such as BLOSUM62.
+func ProgressiveAlignmentWithMatrix(patterns []string, scoring ScoringMatrix, gap float64) MultipleAlignment {
+	return progressiveAlignment(patterns, scoring, gap)
+}
+
+//progressiveAlignment holds the scoring-independent part of ProgressiveAlignment.
+func progressiveAlignment(patterns []string, scorer symbolScorer, gap float64) MultipleAlignment {
+	if len(patterns) == 0 {
+		panic("Error: cannot align zero strings.")
+	}
+	if len(patterns) == 1 {
+		return MultipleAlignment{patterns[0]}
+	}
+
+	labels := make([]string, len(patterns))
+	root := UPGMA(IntToDistanceMatrix(EditDistanceMatrix(patterns)), labels)
+	aligned, order := alignGuideTree(root, patterns, scorer, gap)
+
+	//put the rows back in input order
+	ma := make(MultipleAlignment, len(patterns))
+	for r, index := range order {
+		ma[index] = aligned[r]
+	}
+	return ma
+}
+
+//alignGuideTree aligns the strings below a node of the guide tree. It returns their alignment
+//along with the index of the input string held in each row.
+func alignGuideTree(node *Node, patterns []string, scorer symbolScorer, gap float64) (MultipleAlignment, []int) {
+	if node.IsLeaf() {
+		return MultipleAlignment{patterns[node.Leaf]}, []int{node.Leaf}
+	}
+	profile0, order0 := alignGuideTree(node.Children[0], patterns, scorer, gap)
+	profile1, order1 := alignGuideTree(node.Children[1], patterns, scorer, gap)
+	return alignProfiles(profile0, profile1, scorer, gap), append(order0, order1...)
+}
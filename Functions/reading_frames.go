@@ -0,0 +1,87 @@
+package Functions
+
+//ReadingFrame identifies one of the six ways to read a DNA string as codons: frames 1, 2, and 3
+//start at the first, second, and third symbols of the forward strand, and frames -1, -2, and -3
+//do the same on the reverse complement.
+type ReadingFrame int
+
+//AllReadingFrames lists the six reading frames in the order +1, +2, +3, -1, -2, -3.
+var AllReadingFrames = []ReadingFrame{1, 2, 3, -1, -2, -3}
+
+//FrameTranslation takes a DNA string, a reading frame, and a genetic code. It returns the
+//translation of the string in that frame, with stop codons as "*".
+func FrameTranslation(dna string, frame ReadingFrame, code GeneticCode) string {
+	strand := dna
+	offset := int(frame) - 1
+	if frame < 0 {
+		strand = ReverseComplement(dna)
+		offset = int(-frame) - 1
+	}
+	if frame == 0 || offset > 2 {
+		panic("Error: reading frame must be between -3 and 3 and nonzero.")
+	}
+	if offset >= len(strand) {
+		return ""
+	}
+	return code.Translate(strand[offset:])
+}
+
+//SixFrameTranslations takes a DNA string and a genetic code and returns its translations
+//in the frames +1, +2, +3, -1, -2, -3, in that order.
+func SixFrameTranslations(dna string, code GeneticCode) [6]string {
+	var translations [6]string
+	for i, frame := range AllReadingFrames {
+		translations[i] = FrameTranslation(dna, frame, code)
+	}
+	return translations
+}
+
+//ORF is an open reading frame: a run of codons from a start codon up to and including a stop
+//codon. Start and End are 0-based, half-open coordinates on the forward strand, so that the ORF
+//of a reverse frame is the reverse complement of dna[Start:End]. Protein excludes the stop.
+type ORF struct {
+	Frame   ReadingFrame
+	Start   int
+	End     int
+	Protein string
+}
+
+//FindORFs takes a DNA string, a genetic code, and a minimum protein length. It returns every
+//ORF in all six frames whose protein has at least minLength amino acids. Each ORF begins at
+//the first start codon after the previous stop codon in its frame, and ORFs that run off the
+//end of the string without a stop codon are not reported.
+func FindORFs(dna string, code GeneticCode, minLength int) []ORF {
+	orfs := make([]ORF, 0)
+	n := len(dna)
+
+	for _, frame := range AllReadingFrames {
+		strand := dna
+		offset := int(frame) - 1
+		if frame < 0 {
+			strand = ReverseComplement(dna)
+			offset = int(-frame) - 1
+		}
+
+		start := -1 // index in strand of the current start codon, if any
+		for i := offset; i+3 <= len(strand); i += 3 {
+			codon := strand[i : i+3]
+			if start < 0 && code.IsStart(codon) {
+				start = i
+			}
+			if start >= 0 && code.IsStop(codon) {
+				protein := "M" + code.Translate(strand[start+3:i])
+				if len(protein) >= minLength {
+					orf := ORF{Frame: frame, Start: start, End: i + 3, Protein: protein}
+					if frame < 0 {
+						//convert coordinates back to the forward strand
+						orf.Start, orf.End = n-(i+3), n-start
+					}
+					orfs = append(orfs, orf)
+				}
+				start = -1
+			}
+		}
+	}
+
+	return orfs
+}
@@ -0,0 +1,71 @@
+package Functions
+
+//complements maps each nucleotide symbol, including the IUPAC ambiguity codes, to the symbol
+//of the complementary strand. U is complemented to A, but A is complemented to T.
+var complements = map[byte]byte{
+	'A': 'T', 'T': 'A', 'U': 'A', 'C': 'G', 'G': 'C',
+	'R': 'Y', 'Y': 'R', // purines (A or G) and pyrimidines (C or T)
+	'K': 'M', 'M': 'K', // keto (G or T) and amino (A or C)
+	'S': 'S', 'W': 'W', // strong (C or G) and weak (A or T)
+	'B': 'V', 'V': 'B', // not A and not T
+	'D': 'H', 'H': 'D', // not C and not G
+	'N': 'N',
+}
+
+//complementBase returns the complement of a nucleotide or IUPAC ambiguity code, keeping its
+//case. Other symbols, such as gaps, are returned unchanged.
+func complementBase(c byte) byte {
+	if complement, ok := complements[c]; ok {
+		return complement
+	}
+	if c >= 'a' && c <= 'z' {
+		if complement, ok := complements[c-'a'+'A']; ok {
+			return complement - 'A' + 'a'
+		}
+	}
+	return c
+}
+
+//ReverseComplement takes a DNA string and returns the string read along the opposite strand.
+//IUPAC ambiguity codes are complemented, so that the reverse complement of "ARN" is "NYT".
+func ReverseComplement(dna string) string {
+	b := make([]byte, len(dna))
+	for i := 0; i < len(dna); i++ {
+		b[len(dna)-1-i] = complementBase(dna[i])
+	}
+	return string(b)
+}
+
+//CanonicalKmer returns the smaller, in alphabetical order, of a k-mer and its reverse
+//complement. A k-mer and its reverse complement have the same canonical k-mer, so counting
+//canonical k-mers counts occurrences on both strands together.
+func CanonicalKmer(kmer string) string {
+	rc := ReverseComplement(kmer)
+	if rc < kmer {
+		return rc
+	}
+	return kmer
+}
+
+//CanonicalFrequencyMap is like FrequencyMap, but counts each k-mer under its canonical k-mer.
+func CanonicalFrequencyMap(text string, k int) map[string]int {
+	freq := make(map[string]int)
+	for pattern, count := range FrequencyMap(text, k) {
+		freq[CanonicalKmer(pattern)] += count
+	}
+	return freq
+}
+
+//CountSharedCanonicalKmers is like CountSharedKmers, but a k-mer of str1 is also shared with
+//its reverse complement in str2, so that k-mers on opposite strands are counted.
+func CountSharedCanonicalKmers(str1, str2 string, k int) int {
+	count := 0
+
+	freqMap1 := CanonicalFrequencyMap(str1, k)
+	freqMap2 := CanonicalFrequencyMap(str2, k)
+
+	for pattern := range freqMap1 {
+		count += Min2(freqMap1[pattern], freqMap2[pattern])
+	}
+	return count
+}
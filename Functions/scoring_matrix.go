@@ -0,0 +1,169 @@
+package Functions
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+//ScoringMatrix holds substitution scores (such as BLOSUM or PAM) for every pair of
+//symbols in an alphabet. Scores are positive for favorable substitutions.
+type ScoringMatrix struct {
+	Name   string
+	Scores map[byte]map[byte]float64
+}
+
+//symbolScorer is anything that can score a column of an alignment in which
+//two symbols are aligned against each other.
+type symbolScorer interface {
+	Score(a, b byte) float64
+	MaxScore() float64
+}
+
+//matchMismatch scores every matching pair of symbols as match and every
+//mismatching pair as -mismatch.
+type matchMismatch struct {
+	match    float64
+	mismatch float64
+}
+
+//Score returns the match reward or mismatch penalty for aligning a against b.
+func (m matchMismatch) Score(a, b byte) float64 {
+	if a == b {
+		return m.match
+	}
+	return -m.mismatch
+}
+
+//MaxScore returns the largest score of any column aligning two symbols.
+func (m matchMismatch) MaxScore() float64 {
+	return MaxFloat(m.match, -m.mismatch)
+}
+
+//MaxScore returns the largest score in the matrix.
+func (m ScoringMatrix) MaxScore() float64 {
+	first := true
+	maxScore := 0.0
+	for _, row := range m.Scores {
+		for _, score := range row {
+			if first || score > maxScore {
+				maxScore = score
+				first = false
+			}
+		}
+	}
+	return maxScore
+}
+
+//Score returns the score of aligning symbol a against symbol b. Lower case symbols are
+//scored as upper case, and symbols missing from the matrix are scored using the "*" row
+//and column if the matrix has one.
+func (m ScoringMatrix) Score(a, b byte) float64 {
+	a = upperSymbol(a)
+	b = upperSymbol(b)
+	row, ok := m.Scores[a]
+	if !ok {
+		row, ok = m.Scores['*']
+	}
+	if ok {
+		if score, ok := row[b]; ok {
+			return score
+		}
+		if score, ok := row['*']; ok {
+			return score
+		}
+	}
+	panic("Error: symbols " + string(a) + " and " + string(b) + " are not in scoring matrix " + m.Name + ".")
+}
+
+//upperSymbol converts a lower case letter to upper case and leaves other bytes alone.
+func upperSymbol(c byte) byte {
+	if c >= 'a' && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
+
+//ReadScoringMatrix takes the name of a file holding a substitution matrix in NCBI format
+//(as distributed with BLAST) and returns the corresponding scoring matrix, named after the file.
+func ReadScoringMatrix(filename string) (ScoringMatrix, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return ScoringMatrix{}, err
+	}
+	defer file.Close()
+
+	name := filename
+	if i := strings.LastIndexAny(name, "/\\"); i >= 0 {
+		name = name[i+1:]
+	}
+	return ParseScoringMatrix(name, file)
+}
+
+//ParseScoringMatrix takes a name and a reader holding a substitution matrix in NCBI format.
+//Lines starting with "#" are comments, the first remaining line lists the column symbols,
+//and every other line holds a row symbol followed by one score per column.
+func ParseScoringMatrix(name string, r io.Reader) (ScoringMatrix, error) {
+	m := ScoringMatrix{Name: name, Scores: make(map[byte]map[byte]float64)}
+
+	var columns []byte
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		if columns == nil {
+			//header line of column symbols
+			for _, symbol := range fields {
+				if len(symbol) != 1 {
+					return ScoringMatrix{}, fmt.Errorf("%s line %d: column symbol %q is not a single character", name, lineNumber, symbol)
+				}
+				columns = append(columns, upperSymbol(symbol[0]))
+			}
+			continue
+		}
+
+		if len(fields[0]) != 1 {
+			return ScoringMatrix{}, fmt.Errorf("%s line %d: row symbol %q is not a single character", name, lineNumber, fields[0])
+		}
+		if len(fields)-1 != len(columns) {
+			return ScoringMatrix{}, fmt.Errorf("%s line %d: expected %d scores, found %d", name, lineNumber, len(columns), len(fields)-1)
+		}
+
+		rowSymbol := upperSymbol(fields[0][0])
+		row := make(map[byte]float64, len(columns))
+		for j, field := range fields[1:] {
+			score, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				return ScoringMatrix{}, fmt.Errorf("%s line %d: %v", name, lineNumber, err)
+			}
+			row[columns[j]] = score
+		}
+		m.Scores[rowSymbol] = row
+	}
+	if err := scanner.Err(); err != nil {
+		return ScoringMatrix{}, err
+	}
+
+	if len(m.Scores) == 0 {
+		return ScoringMatrix{}, fmt.Errorf("%s: no scores found", name)
+	}
+
+	return m, nil
+}
+
+//mustParseScoringMatrix parses one of the built-in matrices and panics if it is malformed.
+func mustParseScoringMatrix(name, text string) ScoringMatrix {
+	m, err := ParseScoringMatrix(name, strings.NewReader(text))
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
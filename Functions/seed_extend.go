@@ -0,0 +1,351 @@
+package Functions
+
+import (
+	"math"
+	"sort"
+)
+
+//KmerIndex records where each k-mer of a subject string occurs, so that a query can be
+//searched for seeds without scanning the subject again.
+type KmerIndex struct {
+	K         int
+	Subject   string
+	Positions map[string][]int
+}
+
+//IndexKmers takes a subject string and an integer k. It returns an index mapping each k-mer of
+//the subject to the (0-based) positions where it starts, in increasing order. K-mers are
+//compared exactly, so a query and subject should be given in the same case.
+func IndexKmers(subject string, k int) KmerIndex {
+	if k <= 0 {
+		panic("Error: k must be positive.")
+	}
+	positions := make(map[string][]int)
+	for i := 0; i+k <= len(subject); i++ {
+		kmer := subject[i : i+k]
+		positions[kmer] = append(positions[kmer], i)
+	}
+	return KmerIndex{K: k, Subject: subject, Positions: positions}
+}
+
+//SeedHit is an exact match of a k-mer starting at QueryPos in the query and at SubjectPos in
+//the subject.
+type SeedHit struct {
+	QueryPos   int
+	SubjectPos int
+}
+
+//FindSeedHits takes a query and a k-mer index of a subject. It returns every seed hit, ordered
+//by query position and then by subject position.
+func FindSeedHits(query string, index KmerIndex) []SeedHit {
+	hits := make([]SeedHit, 0)
+	for i := 0; i+index.K <= len(query); i++ {
+		for _, j := range index.Positions[query[i:i+index.K]] {
+			hits = append(hits, SeedHit{i, j})
+		}
+	}
+	return hits
+}
+
+//HSP is a high-scoring segment pair: a local alignment of query[QueryStart:QueryEnd] (the top
+//row of Alignment) against subject[SubjectStart:SubjectEnd] (the bottom row) with its score.
+//Coordinates are 0-based and half-open.
+type HSP struct {
+	QueryStart   int
+	QueryEnd     int
+	SubjectStart int
+	SubjectEnd   int
+	Score        float64
+	Alignment    Alignment
+}
+
+//SearchParameters controls SeedAndExtend. A seed hit is first extended without gaps in both
+//directions until its score falls UngappedXDrop below the best seen. If the ungapped score
+//reaches UngappedCutoff, the hit is extended again allowing gaps, stopping each direction when
+//every cell of the dynamic programming table falls GappedXDrop below the best seen. Gapped
+//extensions scoring less than MinScore aren't reported.
+type SearchParameters struct {
+	UngappedXDrop  float64
+	GappedXDrop    float64
+	UngappedCutoff float64
+	MinScore       float64
+}
+
+//Default scores for searching DNA with SeedAndExtend, the same as BLASTN's. Unrelated sequences
+//must score below zero on average, or gapped extensions wander on without ever dropping off,
+//so the penalties are larger than the match reward.
+const (
+	DefaultSearchMatch    = 1.0
+	DefaultSearchMismatch = 2.0
+	DefaultSearchGap      = 2.0
+)
+
+//DefaultSearchParameters returns search parameters suited to DNA scored with DefaultSearchMatch,
+//DefaultSearchMismatch, and DefaultSearchGap, used with seeds of about 11 symbols.
+func DefaultSearchParameters() SearchParameters {
+	return SearchParameters{UngappedXDrop: 10, GappedXDrop: 25, UngappedCutoff: 12, MinScore: 20}
+}
+
+//SeedAndExtend takes a query, a k-mer index of a subject, match, mismatch, and gap scores, and
+//search parameters. It finds the seed hits of the query, extends them into HSPs, and returns
+//the distinct HSPs in decreasing order of score.
+func SeedAndExtend(query string, index KmerIndex, match, mismatch, gap float64, params SearchParameters) []HSP {
+	return seedAndExtend(query, index, matchMismatch{match, mismatch}, gap, params)
+}
+
+//SeedAndExtendWithMatrix is like SeedAndExtend, but scores symbols with a substitution matrix
+//such as BLOSUM62.
+func SeedAndExtendWithMatrix(query string, index KmerIndex, scoring ScoringMatrix, gap float64, params SearchParameters) []HSP {
+	return seedAndExtend(query, index, scoring, gap, params)
+}
+
+//seedAndExtend holds the scoring-independent part of SeedAndExtend.
+func seedAndExtend(query string, index KmerIndex, scorer symbolScorer, gap float64, params SearchParameters) []HSP {
+	subject := index.Subject
+	hsps := make([]HSP, 0)
+
+	//extended[d] is the query position reached by the last ungapped extension on diagonal d,
+	//so that hits inside an extension already made are skipped
+	extended := make(map[int]int)
+
+	for _, hit := range FindSeedHits(query, index) {
+		diagonal := hit.SubjectPos - hit.QueryPos
+		if reached, ok := extended[diagonal]; ok && hit.QueryPos < reached {
+			continue
+		}
+
+		_, end, score := ungappedExtension(query, subject, hit, index.K, scorer, params.UngappedXDrop)
+		extended[diagonal] = end
+		if score < params.UngappedCutoff || containsHit(hsps, hit) {
+			continue
+		}
+
+		hsp := gappedExtension(query, subject, hit, scorer, gap, params.GappedXDrop)
+		if hsp.Score < params.MinScore {
+			continue
+		}
+		//of two versions of the same alignment, keep the higher scoring one
+		if i := overlappingHSP(hsps, hsp); i < 0 {
+			hsps = append(hsps, hsp)
+		} else if hsp.Score > hsps[i].Score {
+			hsps[i] = hsp
+		}
+	}
+
+	sort.SliceStable(hsps, func(i, j int) bool {
+		if hsps[i].Score != hsps[j].Score {
+			return hsps[i].Score > hsps[j].Score
+		}
+		if hsps[i].QueryStart != hsps[j].QueryStart {
+			return hsps[i].QueryStart < hsps[j].QueryStart
+		}
+		return hsps[i].SubjectStart < hsps[j].SubjectStart
+	})
+	return hsps
+}
+
+//ungappedExtension extends a seed hit along its diagonal in both directions, stopping each way
+//when the score drops more than xDrop below the best so far. It returns the query positions
+//where the best segment starts and ends (half-open) and the segment's score.
+func ungappedExtension(query, subject string, hit SeedHit, k int, scorer symbolScorer, xDrop float64) (int, int, float64) {
+	q, s := hit.QueryPos, hit.SubjectPos
+	score := 0.0
+	for i := 0; i < k; i++ {
+		score += scorer.Score(query[q+i], subject[s+i])
+	}
+
+	//extend to the right of the seed
+	best, end := score, q+k
+	running := score
+	for i, j := q+k, s+k; i < len(query) && j < len(subject); i, j = i+1, j+1 {
+		running += scorer.Score(query[i], subject[j])
+		if running > best {
+			best, end = running, i+1
+		} else if running < best-xDrop {
+			break
+		}
+	}
+
+	//extend to the left of the seed, starting from the best right end
+	start := q
+	running = best
+	for i, j := q-1, s-1; i >= 0 && j >= 0; i, j = i-1, j-1 {
+		running += scorer.Score(query[i], subject[j])
+		if running > best {
+			best, start = running, i
+		} else if running < best-xDrop {
+			break
+		}
+	}
+
+	return start, end, best
+}
+
+//gappedExtension extends a seed hit with gaps from its starting point, forward through the
+//rest of both strings and backward through the strings before it, and joins the two halves.
+func gappedExtension(query, subject string, hit SeedHit, scorer symbolScorer, gap, xDrop float64) HSP {
+	q, s := hit.QueryPos, hit.SubjectPos
+
+	forwardScore, forwardQ, forwardS, forward := xDropAlignment(query[q:], subject[s:], scorer, gap, xDrop)
+	backwardScore, backwardQ, backwardS, backward := xDropAlignment(reverseString(query[:q]), reverseString(subject[:s]), scorer, gap, xDrop)
+
+	return HSP{
+		QueryStart:   q - backwardQ,
+		QueryEnd:     q + forwardQ,
+		SubjectStart: s - backwardS,
+		SubjectEnd:   s + forwardS,
+		Score:        backwardScore + forwardScore,
+		Alignment: Alignment{
+			reverseString(backward[0]) + forward[0],
+			reverseString(backward[1]) + forward[1],
+		},
+	}
+}
+
+//xDropAlignment aligns prefixes of str0 and str1 that start together, filling the global
+//alignment table row by row but dropping every cell whose score is more than xDrop below the
+//best score seen, and stopping when a row has no cells left. It returns the best score, the
+//lengths of the prefixes achieving it, and their alignment.
+func xDropAlignment(str0, str1 string, scorer symbolScorer, gap, xDrop float64) (float64, int, int, Alignment) {
+	dead := math.Inf(-1)
+	best, bestRow, bestCol := 0.0, 0, 0
+
+	//rowStart[i] is the first column kept in row i, and backtrack[i][j-rowStart[i]] its pointer
+	rowStart := make([]int, 0)
+	backtrack := make([][]string, 0)
+
+	//row 0 is a run of gaps in str0, kept while it stays within xDrop of 0
+	prev := make([]float64, len(str1)+1)
+	prevLo, prevHi := 0, 0
+	rowStart = append(rowStart, 0)
+	backtrack = append(backtrack, []string{"FREE"})
+	for j := 1; j <= len(str1) && -gap*float64(j) >= -xDrop; j++ {
+		prev[j] = -gap * float64(j)
+		prevHi = j
+		backtrack[0] = append(backtrack[0], "LEFT")
+	}
+
+	cur := make([]float64, len(str1)+1)
+	for i := 1; i <= len(str0); i++ {
+		lo, hi := -1, -1
+		pointers := make([]string, 0)
+
+		for j := prevLo; j <= len(str1); j++ {
+			inPrev := j >= prevLo && j <= prevHi
+			diagInPrev := j-1 >= prevLo && j-1 <= prevHi
+			leftAlive := lo >= 0 && j-1 >= lo && cur[j-1] != dead
+
+			//beyond the previous row, only a run of gaps in str0 can continue the row
+			if !inPrev && !diagInPrev && !leftAlive {
+				break
+			}
+
+			value, pointer := dead, ""
+			if diagInPrev && prev[j-1] != dead {
+				value, pointer = prev[j-1]+scorer.Score(str0[i-1], str1[j-1]), "DIAG"
+			}
+			if inPrev && prev[j] != dead && prev[j]-gap > value {
+				value, pointer = prev[j]-gap, "UP"
+			}
+			if leftAlive && cur[j-1]-gap > value {
+				value, pointer = cur[j-1]-gap, "LEFT"
+			}
+			if value < best-xDrop {
+				value = dead
+			}
+
+			cur[j] = value
+			if lo < 0 {
+				if value == dead {
+					//don't start the row with dead cells
+					continue
+				}
+				lo = j
+			}
+			pointers = append(pointers, pointer)
+			if value != dead {
+				hi = j
+			}
+		}
+
+		if lo < 0 {
+			break
+		}
+		//trim dead cells from the end of the row
+		pointers = pointers[:hi-lo+1]
+		rowStart = append(rowStart, lo)
+		backtrack = append(backtrack, pointers)
+
+		for j := lo; j <= hi; j++ {
+			if cur[j] > best {
+				best, bestRow, bestCol = cur[j], i, j
+			}
+		}
+		prev, cur = cur, prev
+		prevLo, prevHi = lo, hi
+	}
+
+	//trace back from the best cell
+	var a Alignment
+	row, col := bestRow, bestCol
+	for row > 0 || col > 0 {
+		switch backtrack[row][col-rowStart[row]] {
+		case "DIAG":
+			a[0] = string(str0[row-1]) + a[0]
+			a[1] = string(str1[col-1]) + a[1]
+			row--
+			col--
+		case "UP":
+			a[0] = string(str0[row-1]) + a[0]
+			a[1] = "-" + a[1]
+			row--
+		case "LEFT":
+			a[0] = "-" + a[0]
+			a[1] = string(str1[col-1]) + a[1]
+			col--
+		default:
+			panic("Illegal backtracking pointer.")
+		}
+	}
+
+	return best, bestRow, bestCol, a
+}
+
+//containsHit returns true if a seed hit lies inside the query and subject ranges of an HSP
+//and on one of the diagonals its alignment passes through.
+func containsHit(hsps []HSP, hit SeedHit) bool {
+	for _, hsp := range hsps {
+		if hit.QueryPos < hsp.QueryStart || hit.QueryPos >= hsp.QueryEnd ||
+			hit.SubjectPos < hsp.SubjectStart || hit.SubjectPos >= hsp.SubjectEnd {
+			continue
+		}
+		//walk the alignment, checking whether it passes through the hit
+		q, s := hsp.QueryStart, hsp.SubjectStart
+		for col := 0; col < len(hsp.Alignment[0]); col++ {
+			if q == hit.QueryPos && s == hit.SubjectPos {
+				return true
+			}
+			if hsp.Alignment[0][col] != '-' {
+				q++
+			}
+			if hsp.Alignment[1][col] != '-' {
+				s++
+			}
+		}
+	}
+	return false
+}
+
+//overlappingHSP returns the index of an HSP already found that covers more than half of both
+//the query and subject ranges of hsp, or -1 if there is none. Two seeds often extend into the
+//same alignment with slightly different ends or gap placement, and only one should be reported.
+func overlappingHSP(hsps []HSP, hsp HSP) int {
+	for i, other := range hsps {
+		queryOverlap := Min2(other.QueryEnd, hsp.QueryEnd) - Max(other.QueryStart, hsp.QueryStart)
+		subjectOverlap := Min2(other.SubjectEnd, hsp.SubjectEnd) - Max(other.SubjectStart, hsp.SubjectStart)
+		if 2*queryOverlap > hsp.QueryEnd-hsp.QueryStart && 2*subjectOverlap > hsp.SubjectEnd-hsp.SubjectStart {
+			return i
+		}
+	}
+	return -1
+}
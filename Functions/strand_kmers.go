@@ -0,0 +1,66 @@
+package Functions
+
+import "sort"
+
+//KmerOrientation says on which strands a k-mer is shared by two strings.
+type KmerOrientation string
+
+const (
+	//SameStrand means the k-mer occurs in both strings in the same orientation.
+	SameStrand KmerOrientation = "same"
+	//OppositeStrand means the k-mer of one string occurs as its reverse complement in the other.
+	OppositeStrand KmerOrientation = "opposite"
+	//BothStrands means the k-mer is shared in both orientations, or is its own reverse complement.
+	BothStrands KmerOrientation = "both"
+)
+
+//SharedKmer describes one canonical k-mer shared by two strings. Forward1 and Reverse1 count
+//the occurrences in str1 of the canonical k-mer and of its reverse complement, and Forward2 and
+//Reverse2 do the same for str2. Same is the number of occurrences shared in the same
+//orientation and Opposite the number shared in opposite orientations, each taking the minimum
+//count as CountSharedKmers does.
+type SharedKmer struct {
+	Kmer        string
+	Forward1    int
+	Reverse1    int
+	Forward2    int
+	Reverse2    int
+	Same        int
+	Opposite    int
+	Orientation KmerOrientation
+}
+
+//SharedKmerReport takes two strings and an integer k. It returns a SharedKmer for each canonical
+//k-mer occurring (on either strand) in both strings, in alphabetical order. A k-mer that is its
+//own reverse complement is counted as forward and reported as shared on both strands.
+func SharedKmerReport(str1, str2 string, k int) []SharedKmer {
+	freqMap1 := FrequencyMap(str1, k)
+	freqMap2 := FrequencyMap(str2, k)
+
+	report := make([]SharedKmer, 0)
+	for kmer := range CanonicalFrequencyMap(str1, k) {
+		rc := ReverseComplement(kmer)
+		s := SharedKmer{Kmer: kmer, Forward1: freqMap1[kmer], Forward2: freqMap2[kmer]}
+		if rc != kmer {
+			s.Reverse1 = freqMap1[rc]
+			s.Reverse2 = freqMap2[rc]
+		}
+		if s.Forward2+s.Reverse2 == 0 {
+			continue
+		}
+
+		s.Same = Min2(s.Forward1, s.Forward2) + Min2(s.Reverse1, s.Reverse2)
+		s.Opposite = Min2(s.Forward1, s.Reverse2) + Min2(s.Reverse1, s.Forward2)
+		if rc == kmer || (s.Same > 0 && s.Opposite > 0) {
+			s.Orientation = BothStrands
+		} else if s.Same > 0 {
+			s.Orientation = SameStrand
+		} else {
+			s.Orientation = OppositeStrand
+		}
+		report = append(report, s)
+	}
+
+	sort.Slice(report, func(i, j int) bool { return report[i].Kmer < report[j].Kmer })
+	return report
+}
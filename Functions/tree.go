@@ -0,0 +1,244 @@
+package Functions
+
+import (
+	"strconv"
+	"strings"
+)
+
+//Node is a node of a phylogenetic tree. Leaves have a label and the index of their row in the
+//distance matrix the tree was built from; internal nodes have children and a Leaf of -1.
+//Length is the length of the branch from the node to its parent.
+type Node struct {
+	Label    string
+	Leaf     int
+	Length   float64
+	Children []*Node
+}
+
+//IsLeaf returns true if the node has no children.
+func (n *Node) IsLeaf() bool {
+	return len(n.Children) == 0
+}
+
+//Leaves returns the leaves below a node, from left to right.
+func (n *Node) Leaves() []*Node {
+	if n.IsLeaf() {
+		return []*Node{n}
+	}
+	leaves := make([]*Node, 0)
+	for _, child := range n.Children {
+		leaves = append(leaves, child.Leaves()...)
+	}
+	return leaves
+}
+
+//checkDistanceMatrix panics if a distance matrix isn't square or doesn't have one label per row.
+func checkDistanceMatrix(mtx DistanceMatrix, labels []string) {
+	if len(mtx) == 0 {
+		panic("Error: cannot build a tree from an empty distance matrix.")
+	}
+	if len(labels) != len(mtx) {
+		panic("Error: need one label for each row of the distance matrix.")
+	}
+	for i := range mtx {
+		if len(mtx[i]) != len(mtx) {
+			panic("Error: distance matrix is not square.")
+		}
+	}
+}
+
+//UPGMA takes a distance matrix and a label for each of its rows. It returns the root of the
+//rooted, ultrametric tree built by UPGMA: the two closest clusters are repeatedly joined under
+//a new node at half their distance, and the distance from the new cluster to any other is the
+//average over all pairs of their members. Ties are broken in favor of the earliest rows.
+func UPGMA(mtx DistanceMatrix, labels []string) *Node {
+	checkDistanceMatrix(mtx, labels)
+	n := len(mtx)
+
+	clusters := make([]*Node, n)
+	sizes := make([]int, n)
+	heights := make([]float64, n)
+	//copy distances, since we will overwrite them
+	d := make([][]float64, n)
+	for i := range clusters {
+		clusters[i] = &Node{Label: labels[i], Leaf: i}
+		sizes[i] = 1
+		d[i] = make([]float64, n)
+		copy(d[i], mtx[i])
+	}
+
+	//active clusters are those that haven't been merged yet
+	active := make([]bool, n)
+	for i := range active {
+		active[i] = true
+	}
+
+	for remaining := n; remaining > 1; remaining-- {
+		//find the closest pair of active clusters
+		bestI, bestJ := -1, -1
+		for i := 0; i < n; i++ {
+			if !active[i] {
+				continue
+			}
+			for j := i + 1; j < n; j++ {
+				if active[j] && (bestI < 0 || d[i][j] < d[bestI][bestJ]) {
+					bestI, bestJ = i, j
+				}
+			}
+		}
+
+		//join the clusters under a new node halfway between them
+		height := d[bestI][bestJ] / 2
+		clusters[bestI].Length = height - heights[bestI]
+		clusters[bestJ].Length = height - heights[bestJ]
+		parent := &Node{Leaf: -1, Children: []*Node{clusters[bestI], clusters[bestJ]}}
+
+		//the new cluster takes the place of bestI, averaging distances by cluster size
+		for k := 0; k < n; k++ {
+			if active[k] && k != bestI && k != bestJ {
+				avg := (d[bestI][k]*float64(sizes[bestI]) + d[bestJ][k]*float64(sizes[bestJ])) /
+					float64(sizes[bestI]+sizes[bestJ])
+				d[bestI][k] = avg
+				d[k][bestI] = avg
+			}
+		}
+		clusters[bestI] = parent
+		sizes[bestI] += sizes[bestJ]
+		heights[bestI] = height
+		active[bestJ] = false
+	}
+
+	for i := range active {
+		if active[i] {
+			return clusters[i]
+		}
+	}
+	panic("Error: no cluster left in UPGMA.")
+}
+
+//NeighborJoining takes a distance matrix and a label for each of its rows. It returns the
+//unrooted tree built by neighbor-joining, which reconstructs any additive matrix exactly.
+//The tree is drawn from the last internal node created, so the root of a tree with three
+//or more leaves has three children. Ties are broken in favor of the earliest rows.
+func NeighborJoining(mtx DistanceMatrix, labels []string) *Node {
+	checkDistanceMatrix(mtx, labels)
+
+	//nodes[i] is the subtree whose distances are in row i of d
+	nodes := make([]*Node, len(mtx))
+	d := make([][]float64, len(mtx))
+	for i := range mtx {
+		nodes[i] = &Node{Label: labels[i], Leaf: i}
+		d[i] = make([]float64, len(mtx))
+		copy(d[i], mtx[i])
+	}
+
+	if len(nodes) == 1 {
+		return nodes[0]
+	}
+	if len(nodes) == 2 {
+		//place the root halfway along the only branch
+		nodes[0].Length = d[0][1] / 2
+		nodes[1].Length = d[0][1] / 2
+		return &Node{Leaf: -1, Children: nodes}
+	}
+
+	for len(nodes) > 2 {
+		n := len(nodes)
+
+		totals := make([]float64, n)
+		for i := range d {
+			for j := range d[i] {
+				totals[i] += d[i][j]
+			}
+		}
+
+		//find the pair minimizing the neighbor-joining criterion
+		bestI, bestJ := 0, 1
+		bestValue := 0.0
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				value := float64(n-2)*d[i][j] - totals[i] - totals[j]
+				if (i == 0 && j == 1) || value < bestValue {
+					bestI, bestJ, bestValue = i, j, value
+				}
+			}
+		}
+
+		//limb lengths of the two neighbors
+		delta := (totals[bestI] - totals[bestJ]) / float64(n-2)
+		nodes[bestI].Length = (d[bestI][bestJ] + delta) / 2
+		nodes[bestJ].Length = (d[bestI][bestJ] - delta) / 2
+		parent := &Node{Leaf: -1, Children: []*Node{nodes[bestI], nodes[bestJ]}}
+
+		//distances from the new node to the others
+		newRow := make([]float64, 0, n-1)
+		newNodes := make([]*Node, 0, n-1)
+		for k := 0; k < n; k++ {
+			if k != bestI && k != bestJ {
+				newRow = append(newRow, (d[bestI][k]+d[bestJ][k]-d[bestI][bestJ])/2)
+				newNodes = append(newNodes, nodes[k])
+			}
+		}
+
+		//remove rows and columns bestI and bestJ, then add the new node at the end
+		newD := make([][]float64, 0, n-1)
+		for k := 0; k < n; k++ {
+			if k == bestI || k == bestJ {
+				continue
+			}
+			row := make([]float64, 0, n-1)
+			for m := 0; m < n; m++ {
+				if m != bestI && m != bestJ {
+					row = append(row, d[k][m])
+				}
+			}
+			row = append(row, newRow[len(newD)])
+			newD = append(newD, row)
+		}
+		newD = append(newD, append(newRow, 0))
+
+		d = newD
+		nodes = append(newNodes, parent)
+	}
+
+	//two nodes remain, and the second is the node just created: hang the first from it
+	root := nodes[1]
+	nodes[0].Length = d[0][1]
+	root.Children = append(root.Children, nodes[0])
+	return root
+}
+
+//Newick returns the tree below a node in Newick format, ending with a semicolon. Branch
+//lengths are written for every node except the root.
+func (n *Node) Newick() string {
+	var b strings.Builder
+	n.writeNewick(&b)
+	b.WriteByte(';')
+	return b.String()
+}
+
+//writeNewick writes a subtree in Newick format without the branch length of its root.
+func (n *Node) writeNewick(b *strings.Builder) {
+	if !n.IsLeaf() {
+		b.WriteByte('(')
+		for i, child := range n.Children {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			child.writeNewick(b)
+			b.WriteByte(':')
+			b.WriteString(strconv.FormatFloat(child.Length, 'g', -1, 64))
+		}
+		b.WriteByte(')')
+	}
+	b.WriteString(newickLabel(n.Label))
+}
+
+//newickLabel quotes a label if it holds a character with a special meaning in Newick.
+//Within quotes, a single quote is written twice.
+func newickLabel(label string) string {
+	if !strings.ContainsAny(label, " \t()[]':;,") {
+		return label
+	}
+	return "'" + strings.Replace(label, "'", "''", -1) + "'"
+}
@@ -0,0 +1,134 @@
+package Functions
+
+import "strings"
+
+//VariantType is the kind of difference between a reference and a query.
+type VariantType string
+
+const (
+	SNP       VariantType = "SNP"
+	Insertion VariantType = "INS"
+	Deletion  VariantType = "DEL"
+	//Complex marks a run of gap columns holding both inserted and deleted symbols, or an
+	//indel whose anchor symbol is itself substituted.
+	Complex VariantType = "COMPLEX"
+)
+
+//Variant is a difference between the reference (top row) and query (bottom row) of an
+//alignment, described as in a VCF file: Pos is the 1-based reference position of the first
+//symbol of Ref, and Ref is replaced by Alt in the query. Indels include an unchanged anchor
+//symbol of the reference, which is the symbol before the event (or after it, if the event
+//starts the reference). If the query has a different symbol at the anchor, or the anchor is
+//shared with a neighbouring event, they are reported together as one Complex variant.
+type Variant struct {
+	Pos  int
+	Ref  string
+	Alt  string
+	Type VariantType
+	//Column is the index of the alignment column where the variant starts.
+	Column int
+}
+
+//CallVariants takes an alignment whose top row is the reference. It returns the SNPs and indels
+//of the query in order of reference position. Adjacent gap columns are merged into a single
+//insertion, deletion, or complex event, so that no two variants cover the same position.
+func CallVariants(a Alignment) []Variant {
+	if len(a[0]) != len(a[1]) {
+		panic("Error: alignment rows have different lengths.")
+	}
+
+	reference := strings.Replace(a[0], "-", "", -1)
+	variants := make([]Variant, 0)
+
+	refPos := 0 // number of reference symbols consumed so far
+	col := 0
+	for col < len(a[0]) {
+		c0 := a[0][col]
+		c1 := a[1][col]
+
+		if c0 != '-' && c1 != '-' {
+			if c0 != c1 {
+				variants = append(variants, Variant{Pos: refPos + 1, Ref: string(c0), Alt: string(c1), Type: SNP, Column: col})
+			}
+			refPos++
+			col++
+			continue
+		}
+
+		//gather the maximal run of columns that contain a gap
+		start := col
+		deleted := ""
+		inserted := ""
+		for col < len(a[0]) && (a[0][col] == '-' || a[1][col] == '-') {
+			if a[0][col] != '-' {
+				deleted += string(a[0][col])
+			}
+			if a[1][col] != '-' {
+				inserted += string(a[1][col])
+			}
+			col++
+		}
+
+		if deleted == inserted {
+			//only columns of two gaps, or symbols that moved without changing
+			refPos += len(deleted)
+			continue
+		}
+
+		v := Variant{Column: start}
+		if len(deleted) == 0 {
+			v.Type = Insertion
+		} else if len(inserted) == 0 {
+			v.Type = Deletion
+		} else {
+			v.Type = Complex
+		}
+
+		//the anchor may already belong to the last variant: a SNP at the anchor, or an event
+		//at the start of the reference anchored on the symbol after it. Extend that variant
+		//rather than report two variants covering the anchor.
+		if n := len(variants); refPos > 0 && n > 0 && variants[n-1].Pos+len(variants[n-1].Ref)-1 == refPos {
+			last := &variants[n-1]
+			last.Ref += deleted
+			last.Alt += inserted
+			last.Type = Complex
+			refPos += len(deleted)
+			continue
+		}
+
+		//the anchor's symbol in Alt comes from the query, which may have substituted it
+		anchorSubstituted := false
+		if refPos > 0 {
+			//the column before a maximal gap run holds the same symbol in both rows, since
+			//a substituted anchor would have been called as a SNP above
+			anchorColumn := start - 1
+			v.Pos = refPos
+			v.Ref = string(a[0][anchorColumn]) + deleted
+			v.Alt = string(a[1][anchorColumn]) + inserted
+		} else if refPos+len(deleted) < len(reference) {
+			//the column after the gap run holds the anchor in both rows
+			v.Pos = 1
+			v.Ref = deleted + string(a[0][col])
+			v.Alt = inserted + string(a[1][col])
+			if a[0][col] != a[1][col] {
+				//skip the anchor's column, so no SNP is called there
+				anchorSubstituted = true
+				refPos++
+				col++
+			}
+		} else {
+			//the event covers the whole reference, so there is nothing to anchor it to
+			v.Pos = 1
+			v.Ref = deleted
+			v.Alt = inserted
+		}
+		if anchorSubstituted {
+			v.Type = Complex
+		}
+		variants = append(variants, v)
+
+		refPos += len(deleted)
+	}
+
+	return variants
+}
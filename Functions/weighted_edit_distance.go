@@ -0,0 +1,125 @@
+package Functions
+
+//EditCosts holds the costs of the edits counted by WeightedEditDistance. Substitution[a][b] is
+//the cost of replacing a with b, Insertion[c] the cost of inserting c, and Deletion[c] the cost
+//of deleting c. Symbols are looked up in uppercase, and any pair or symbol missing from the
+//tables costs DefaultSubstitution or DefaultIndel. Replacing a symbol by itself costs nothing.
+type EditCosts struct {
+	Substitution        map[byte]map[byte]float64
+	Insertion           map[byte]float64
+	Deletion            map[byte]float64
+	DefaultSubstitution float64
+	DefaultIndel        float64
+}
+
+//UnitEditCosts returns the costs of the ordinary edit distance: every edit costs 1.
+func UnitEditCosts() EditCosts {
+	return EditCosts{DefaultSubstitution: 1, DefaultIndel: 1}
+}
+
+//TransitionTransversionCosts returns costs for DNA in which a transition (A <-> G or C <-> T)
+//costs transition, any other substitution costs transversion, and each indel costs indel.
+func TransitionTransversionCosts(transition, transversion, indel float64) EditCosts {
+	costs := EditCosts{
+		Substitution:        make(map[byte]map[byte]float64),
+		DefaultSubstitution: transversion,
+		DefaultIndel:        indel,
+	}
+	for _, pair := range []string{"AG", "GA", "CT", "TC", "UC", "CU"} {
+		if costs.Substitution[pair[0]] == nil {
+			costs.Substitution[pair[0]] = make(map[byte]float64)
+		}
+		costs.Substitution[pair[0]][pair[1]] = transition
+	}
+	return costs
+}
+
+//SubstitutionCost returns the cost of replacing a with b.
+func (c EditCosts) SubstitutionCost(a, b byte) float64 {
+	a, b = upperSymbol(a), upperSymbol(b)
+	if a == b {
+		return 0
+	}
+	if cost, ok := c.Substitution[a][b]; ok {
+		return cost
+	}
+	return c.DefaultSubstitution
+}
+
+//InsertionCost returns the cost of inserting a symbol.
+func (c EditCosts) InsertionCost(symbol byte) float64 {
+	if cost, ok := c.Insertion[upperSymbol(symbol)]; ok {
+		return cost
+	}
+	return c.DefaultIndel
+}
+
+//DeletionCost returns the cost of deleting a symbol.
+func (c EditCosts) DeletionCost(symbol byte) float64 {
+	if cost, ok := c.Deletion[upperSymbol(symbol)]; ok {
+		return cost
+	}
+	return c.DefaultIndel
+}
+
+//WeightedEditDistance takes two strings and a table of edit costs. It returns the minimum
+//total cost of substitutions, insertions, and deletions transforming str1 into str2.
+func WeightedEditDistance(str1, str2 string, costs EditCosts) float64 {
+	return WeightedEditMatrix(str1, str2, costs)[len(str1)][len(str2)]
+}
+
+//WeightedEditMatrix is like EditMatrix, but its (i, j)-th value is the weighted edit distance
+//between the first i symbols of str1 and the first j symbols of str2.
+func WeightedEditMatrix(str1, str2 string, costs EditCosts) [][]float64 {
+	numRows := len(str1) + 1
+	numCols := len(str2) + 1
+	scoringMatrix := InitializeFloatTable(numRows, numCols)
+
+	//the 0-th column deletes a prefix of str1, and the 0-th row inserts a prefix of str2
+	for i := 1; i < numRows; i++ {
+		scoringMatrix[i][0] = scoringMatrix[i-1][0] + costs.DeletionCost(str1[i-1])
+	}
+	for j := 1; j < numCols; j++ {
+		scoringMatrix[0][j] = scoringMatrix[0][j-1] + costs.InsertionCost(str2[j-1])
+	}
+
+	for row := 1; row < numRows; row++ {
+		for col := 1; col < numCols; col++ {
+			up := scoringMatrix[row-1][col] + costs.DeletionCost(str1[row-1])
+			left := scoringMatrix[row][col-1] + costs.InsertionCost(str2[col-1])
+			diag := scoringMatrix[row-1][col-1] + costs.SubstitutionCost(str1[row-1], str2[col-1])
+			scoringMatrix[row][col] = MinFloat(up, left, diag)
+		}
+	}
+	return scoringMatrix
+}
+
+//WeightedEditDistanceMatrix is like EditDistanceMatrix, but uses the weighted edit distance.
+func WeightedEditDistanceMatrix(patterns []string, costs EditCosts) DistanceMatrix {
+	mtx := make(DistanceMatrix, len(patterns))
+	for i := range mtx {
+		mtx[i] = make([]float64, len(patterns))
+	}
+	for i := range patterns {
+		for j := i + 1; j < len(patterns); j++ {
+			mtx[i][j] = WeightedEditDistance(patterns[i], patterns[j], costs)
+			mtx[j][i] = WeightedEditDistance(patterns[j], patterns[i], costs)
+		}
+	}
+	return mtx
+}
+
+//MinFloat is a variadic function that takes an arbitrary number of floats as input and
+//returns their minimum.
+func MinFloat(nums ...float64) float64 {
+	if len(nums) == 0 {
+		panic("no")
+	}
+	m := nums[0]
+	for i := 1; i < len(nums); i++ {
+		if nums[i] < m {
+			m = nums[i]
+		}
+	}
+	return m
+}
@@ -0,0 +1,850 @@
+package main
+
+import (
+	"Alignment/Functions"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+)
+
+//scoringFlags holds the scoring parameters shared by the alignment subcommands.
+type scoringFlags struct {
+	match     float64
+	mismatch  float64
+	gap       float64
+	gapOpen   float64
+	gapExtend float64
+	matrix    string
+}
+
+//register adds the scoring flags to a flag set.
+func (s *scoringFlags) register(fs *flag.FlagSet) {
+	s.registerWithDefaults(fs, 1.0, 1.0, 1.0)
+}
+
+//registerWithDefaults is like register, but with the given defaults for -match, -mismatch,
+//and -gap.
+func (s *scoringFlags) registerWithDefaults(fs *flag.FlagSet, match, mismatch, gap float64) {
+	fs.Float64Var(&s.match, "match", match, "reward for aligning matching symbols")
+	fs.Float64Var(&s.mismatch, "mismatch", mismatch, "penalty for aligning mismatching symbols")
+	fs.Float64Var(&s.gap, "gap", gap, "penalty for each gap symbol (linear gaps)")
+	fs.Float64Var(&s.gapOpen, "gap-open", 0.0, "penalty for opening a gap; setting this uses affine gaps")
+	fs.Float64Var(&s.gapExtend, "gap-extend", 0.0, "penalty for extending a gap (affine gaps)")
+	fs.StringVar(&s.matrix, "matrix", "", "substitution matrix (BLOSUM45, BLOSUM62, BLOSUM80, PAM250, or an NCBI-format file) used instead of -match and -mismatch")
+}
+
+//affine returns true if affine gap penalties were requested.
+func (s scoringFlags) affine() bool {
+	return s.gapOpen > 0
+}
+
+//scoringMatrix returns the substitution matrix named by -matrix, if any.
+func (s scoringFlags) scoringMatrix() (Functions.ScoringMatrix, bool, error) {
+	if s.matrix == "" {
+		return Functions.ScoringMatrix{}, false, nil
+	}
+	if m, ok := Functions.BuiltInScoringMatrix(strings.ToUpper(s.matrix)); ok {
+		return m, true, nil
+	}
+	m, err := Functions.ReadScoringMatrix(s.matrix)
+	return m, true, err
+}
+
+//pairFlags holds the two input files of a pairwise subcommand.
+type pairFlags struct {
+	in0 string
+	in1 string
+}
+
+func (p *pairFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&p.in0, "in0", "", "FASTA file holding the first sequence (required)")
+	fs.StringVar(&p.in1, "in1", "", "FASTA file holding the second sequence (required)")
+}
+
+//read returns the single records held by the two input files.
+func (p pairFlags) read() (Record, Record, error) {
+	if p.in0 == "" || p.in1 == "" {
+		return Record{}, Record{}, errors.New("both -in0 and -in1 are required")
+	}
+	r0, err := ReadFASTARecord(p.in0)
+	if err != nil {
+		return Record{}, Record{}, err
+	}
+	r1, err := ReadFASTARecord(p.in1)
+	if err != nil {
+		return Record{}, Record{}, err
+	}
+	return r0, r1, nil
+}
+
+//writeOutput calls write with the destination of the -out flag, which is standard output if
+//path is empty, and closes the file, if one was opened.
+func writeOutput(path string, write func(w io.Writer) error) error {
+	if path == "" {
+		return write(os.Stdout)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+//writeOutputString writes text to the destination of the -out flag.
+func writeOutputString(path, text string) error {
+	return writeOutput(path, func(w io.Writer) error {
+		_, err := io.WriteString(w, text)
+		return err
+	})
+}
+
+//readFASTAFiles returns every record of one or more FASTA files, in order.
+func readFASTAFiles(filenames []string) ([]Record, error) {
+	records := make([]Record, 0)
+	for _, filename := range filenames {
+		fileRecords, err := ReadFASTARecords(filename)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, fileRecords...)
+	}
+	return records, nil
+}
+
+//recordSequences returns the sequences of a slice of records.
+func recordSequences(records []Record) []string {
+	sequences := make([]string, len(records))
+	for i := range records {
+		sequences[i] = records[i].Sequence
+	}
+	return sequences
+}
+
+//parseFlags parses the arguments of a subcommand, returning flag.ErrHelp if help was requested.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	fs.SetOutput(os.Stderr)
+	return fs.Parse(args)
+}
+
+//runAlign implements "align <mode>", which aligns the sequences of two FASTA files.
+func runAlign(args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return errors.New("align needs a mode: global, local, semi-global, fitting, or overlap")
+	}
+	mode := args[0]
+
+	fs := flag.NewFlagSet("align "+mode, flag.ContinueOnError)
+	var scoring scoringFlags
+	var inputs pairFlags
+	scoring.register(fs)
+	inputs.register(fs)
+	method := fs.String("method", "full", "global alignment algorithm: full, linear (Hirschberg), or banded")
+	out := fs.String("out", "", "output file (default standard output)")
+	format := fs.String("format", "fasta", "output format: fasta, text, sam, or vcf (sam and vcf use -in0 as the reference)")
+	if err := parseFlags(fs, args[1:]); err != nil {
+		return err
+	}
+
+	r0, r1, err := inputs.read()
+	if err != nil {
+		return err
+	}
+	matrix, useMatrix, err := scoring.scoringMatrix()
+	if err != nil {
+		return err
+	}
+
+	var a Functions.Alignment
+	start0, end0, start1, end1 := 0, len(r0.Sequence), 0, len(r1.Sequence)
+
+	switch mode {
+	case "global":
+		a, err = globalAlign(r0.Sequence, r1.Sequence, scoring, matrix, useMatrix, *method)
+		if err != nil {
+			return err
+		}
+	case "local":
+		if scoring.affine() && useMatrix {
+			a, start0, end0, start1, end1 = Functions.LocalAffineAlignmentWithMatrix(r0.Sequence, r1.Sequence, matrix, scoring.gapOpen, scoring.gapExtend)
+		} else if scoring.affine() {
+			a, start0, end0, start1, end1 = Functions.LocalAffineAlignment(r0.Sequence, r1.Sequence, scoring.match, scoring.mismatch, scoring.gapOpen, scoring.gapExtend)
+		} else if useMatrix {
+			a, start0, end0, start1, end1 = Functions.LocalAlignmentWithMatrix(r0.Sequence, r1.Sequence, matrix, scoring.gap)
+		} else {
+			a, start0, end0, start1, end1 = Functions.LocalAlignment(r0.Sequence, r1.Sequence, scoring.match, scoring.mismatch, scoring.gap)
+		}
+	case "semi-global", "fitting", "overlap":
+		if scoring.affine() {
+			return fmt.Errorf("%s alignment supports only linear gap penalties", mode)
+		}
+		alignmentMode := map[string]Functions.AlignmentMode{
+			"semi-global": Functions.SemiGlobalMode,
+			"fitting":     Functions.FittingMode,
+			"overlap":     Functions.OverlapMode,
+		}[mode]
+		if useMatrix {
+			a, start0, end0, start1, end1 = Functions.ModeAlignmentWithMatrix(r0.Sequence, r1.Sequence, matrix, scoring.gap, alignmentMode)
+		} else {
+			a, start0, end0, start1, end1 = Functions.ModeAlignment(r0.Sequence, r1.Sequence, scoring.match, scoring.mismatch, scoring.gap, alignmentMode)
+		}
+	default:
+		return fmt.Errorf("unknown alignment mode %q", mode)
+	}
+
+	rows := []Record{
+		{ID: r0.ID, Description: fmt.Sprintf("%d-%d", start0+1, end0), Sequence: a[0]},
+		{ID: r1.ID, Description: fmt.Sprintf("%d-%d", start1+1, end1), Sequence: a[1]},
+	}
+	return writeOutput(*out, func(w io.Writer) error {
+		switch *format {
+		case "fasta":
+			return WriteFASTA(w, rows, 0)
+		case "text":
+			_, err := fmt.Fprintf(w, "%s\n%s\n", a[0], a[1])
+			return err
+		case "sam":
+			return WriteSAM(w, r0, r1, a, start0, start1)
+		case "vcf":
+			return WriteVCF(w, r0, r1.ID, Functions.CallVariants(a), start0)
+		}
+		return fmt.Errorf("unknown output format %q", *format)
+	})
+}
+
+//globalAlign chooses the global alignment function matching the scoring flags and method.
+func globalAlign(str0, str1 string, scoring scoringFlags, matrix Functions.ScoringMatrix, useMatrix bool, method string) (Functions.Alignment, error) {
+	if scoring.affine() {
+		if method != "full" {
+			return Functions.Alignment{}, errors.New("affine gap penalties support only the full method")
+		}
+		if useMatrix {
+			return Functions.GlobalAffineAlignmentWithMatrix(str0, str1, matrix, scoring.gapOpen, scoring.gapExtend), nil
+		}
+		return Functions.GlobalAffineAlignment(str0, str1, scoring.match, scoring.mismatch, scoring.gapOpen, scoring.gapExtend), nil
+	}
+
+	switch method {
+	case "full":
+		if useMatrix {
+			return Functions.GlobalAlignmentWithMatrix(str0, str1, matrix, scoring.gap), nil
+		}
+		return Functions.GlobalAlignment(str0, str1, scoring.match, scoring.mismatch, scoring.gap), nil
+	case "linear":
+		if useMatrix {
+			return Functions.GlobalAlignmentLinearSpaceWithMatrix(str0, str1, matrix, scoring.gap), nil
+		}
+		return Functions.GlobalAlignmentLinearSpace(str0, str1, scoring.match, scoring.mismatch, scoring.gap), nil
+	case "banded":
+		if useMatrix {
+			return Functions.BandedGlobalAlignmentWithMatrix(str0, str1, matrix, scoring.gap), nil
+		}
+		return Functions.BandedGlobalAlignment(str0, str1, scoring.match, scoring.mismatch, scoring.gap), nil
+	}
+	return Functions.Alignment{}, fmt.Errorf("unknown method %q", method)
+}
+
+//runDistance implements "distance", which writes the edit distance matrix of every record
+//in the given FASTA files, in the tab-separated format read by ReadMatrixFromFile.
+func runDistance(args []string) error {
+	fs := flag.NewFlagSet("distance", flag.ContinueOnError)
+	var scoring scoringFlags
+	scoring.register(fs)
+	var matrixOptions matrixFlags
+	matrixOptions.register(fs)
+	out := fs.String("out", "", "output file (default standard output)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: Alignment distance [flags] file.fasta ...")
+		fs.PrintDefaults()
+	}
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("distance needs at least one FASTA file")
+	}
+
+	records, err := readFASTAFiles(fs.Args())
+	if err != nil {
+		return err
+	}
+	mtx, err := distanceMatrix(recordSequences(records), matrixOptions, scoring)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	b.WriteString(strconv.Itoa(len(records)) + "\n")
+	for i := range records {
+		b.WriteString(records[i].ID)
+		for j := range mtx[i] {
+			b.WriteString("\t" + strconv.FormatFloat(mtx[i][j], 'g', -1, 64))
+		}
+		b.WriteString("\n")
+	}
+	return writeOutputString(*out, b.String())
+}
+
+//distanceModels maps the values of -model, other than edit, to distance models.
+var distanceModels = map[string]Functions.DistanceModel{
+	"p":       Functions.PDistanceModel,
+	"jc69":    Functions.JukesCantorModel,
+	"k80":     Functions.Kimura2PModel,
+	"tn93":    Functions.TamuraNeiModel,
+	"poisson": Functions.PoissonModel,
+	"kimura":  Functions.KimuraProteinModel,
+}
+
+//matrixFlags holds the flags controlling how a distance matrix is computed.
+type matrixFlags struct {
+	model    string
+	workers  int
+	progress bool
+}
+
+func (m *matrixFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&m.model, "model", "edit", "distance: edit, p, jc69, k80, or tn93 for DNA, or poisson or kimura for proteins; all but edit globally align each pair with the scoring flags")
+	fs.IntVar(&m.workers, "workers", 0, "number of pairs of sequences compared at once (default one per CPU)")
+	fs.BoolVar(&m.progress, "progress", false, "report the number of finished pairs on standard error")
+}
+
+//distanceMatrix returns the matrix of distances between sequences under the model named by
+//-model. Pairs are compared concurrently, and an interrupt (Ctrl-C) stops the computation.
+func distanceMatrix(sequences []string, flags matrixFlags, scoring scoringFlags) (Functions.DistanceMatrix, error) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	opts := Functions.MatrixOptions{Workers: flags.workers}
+	if flags.progress {
+		opts.Progress = func(done, total int) {
+			fmt.Fprintf(os.Stderr, "\r%d/%d pairs", done, total)
+			if done == total {
+				fmt.Fprintln(os.Stderr)
+			}
+		}
+	}
+
+	model := flags.model
+	if model == "edit" {
+		mtx, err := Functions.EditDistanceMatrixParallel(ctx, sequences, opts)
+		if err != nil {
+			return nil, err
+		}
+		return Functions.IntToDistanceMatrix(mtx), nil
+	}
+	m, ok := distanceModels[strings.ToLower(model)]
+	if !ok {
+		return nil, fmt.Errorf("unknown distance model %q", model)
+	}
+	if scoring.affine() {
+		return nil, errors.New("distance models support only linear gap penalties")
+	}
+	matrix, useMatrix, err := scoring.scoringMatrix()
+	if err != nil {
+		return nil, err
+	}
+	if useMatrix {
+		return Functions.ModelDistanceMatrixParallelWithMatrix(ctx, sequences, m, matrix, scoring.gap, opts)
+	}
+	return Functions.ModelDistanceMatrixParallel(ctx, sequences, m, scoring.match, scoring.mismatch, scoring.gap, opts)
+}
+
+//runMSA implements "msa", which progressively aligns every record of one or more FASTA files.
+func runMSA(args []string) error {
+	fs := flag.NewFlagSet("msa", flag.ContinueOnError)
+	var scoring scoringFlags
+	scoring.register(fs)
+	out := fs.String("out", "", "output file (default standard output)")
+	format := fs.String("format", "fasta", "output format: fasta or text")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: Alignment msa [flags] file.fasta ...")
+		fs.PrintDefaults()
+	}
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("msa needs at least one FASTA file")
+	}
+	if scoring.affine() {
+		return errors.New("msa supports only linear gap penalties")
+	}
+
+	records, err := readFASTAFiles(fs.Args())
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return errors.New("no records to align")
+	}
+	matrix, useMatrix, err := scoring.scoringMatrix()
+	if err != nil {
+		return err
+	}
+
+	var ma Functions.MultipleAlignment
+	if useMatrix {
+		ma = Functions.ProgressiveAlignmentWithMatrix(recordSequences(records), matrix, scoring.gap)
+	} else {
+		ma = Functions.ProgressiveAlignment(recordSequences(records), scoring.match, scoring.mismatch, scoring.gap)
+	}
+
+	switch *format {
+	case "fasta":
+		rows := make([]Record, len(records))
+		for i := range records {
+			rows[i] = Record{ID: records[i].ID, Description: records[i].Description, Sequence: ma[i]}
+		}
+		return writeOutput(*out, func(w io.Writer) error {
+			return WriteFASTA(w, rows, 0)
+		})
+	case "text":
+		return writeOutputString(*out, strings.Join(ma, "\n")+"\n")
+	}
+	return fmt.Errorf("unknown output format %q", *format)
+}
+
+//runTree implements "tree", which builds a Newick tree from the edit distances between the
+//records of one or more FASTA files, or from a distance matrix file.
+func runTree(args []string) error {
+	fs := flag.NewFlagSet("tree", flag.ContinueOnError)
+	var scoring scoringFlags
+	scoring.register(fs)
+	method := fs.String("method", "nj", "tree construction method: upgma or nj (neighbor-joining)")
+	var matrixOptions matrixFlags
+	matrixOptions.register(fs)
+	distances := fs.String("distances", "", "distance matrix file in the format written by the distance command, used instead of FASTA files")
+	out := fs.String("out", "", "output file (default standard output)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: Alignment tree [flags] file.fasta ...")
+		fs.PrintDefaults()
+	}
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	var mtx Functions.DistanceMatrix
+	var labels []string
+	if *distances != "" {
+		if fs.NArg() > 0 {
+			return errors.New("give either -distances or FASTA files, not both")
+		}
+		var err error
+		mtx, labels, err = Functions.ReadDistanceMatrix(*distances)
+		if err != nil {
+			return err
+		}
+	} else {
+		if fs.NArg() == 0 {
+			return errors.New("tree needs -distances or at least one FASTA file")
+		}
+		records, err := readFASTAFiles(fs.Args())
+		if err != nil {
+			return err
+		}
+		mtx, err = distanceMatrix(recordSequences(records), matrixOptions, scoring)
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			labels = append(labels, record.ID)
+		}
+	}
+	if len(mtx) == 0 {
+		return errors.New("no sequences to build a tree from")
+	}
+
+	var root *Functions.Node
+	switch *method {
+	case "upgma":
+		root = Functions.UPGMA(mtx, labels)
+	case "nj":
+		root = Functions.NeighborJoining(mtx, labels)
+	default:
+		return fmt.Errorf("unknown tree method %q", *method)
+	}
+
+	return writeOutputString(*out, root.Newick()+"\n")
+}
+
+//maxEditMatrixCells is the largest edit matrix that runEdits fills; larger pairs of sequences
+//are aligned in linear space instead.
+const maxEditMatrixCells = 1 << 24
+
+//runEdits implements "edits", which prints a shortest edit script turning one sequence into another.
+func runEdits(args []string) error {
+	fs := flag.NewFlagSet("edits", flag.ContinueOnError)
+	var inputs pairFlags
+	inputs.register(fs)
+	format := fs.String("format", "diff", "output format: diff or list")
+	out := fs.String("out", "", "output file (default standard output)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	r0, r1, err := inputs.read()
+	if err != nil {
+		return err
+	}
+
+	var script []Functions.EditOperation
+	if len(r0.Sequence)*len(r1.Sequence) <= maxEditMatrixCells {
+		script = Functions.EditScript(r0.Sequence, r1.Sequence)
+	} else {
+		a := Functions.GlobalAlignmentLinearSpace(r0.Sequence, r1.Sequence, 0, 1, 1)
+		script = Functions.AlignmentEditScript(a)
+	}
+
+	switch *format {
+	case "diff":
+		return writeOutputString(*out, Functions.EditDiff(r0.Sequence, script))
+	case "list":
+		var b strings.Builder
+		for _, op := range script {
+			b.WriteString(op.String() + "\n")
+		}
+		return writeOutputString(*out, b.String())
+	}
+	return fmt.Errorf("unknown output format %q", *format)
+}
+
+//runSearch implements "search", which finds the sites of a pattern, such as a primer, with at
+//most -d mismatches on both strands of every record of one or more FASTA files.
+func runSearch(args []string) error {
+	fs := flag.NewFlagSet("search", flag.ContinueOnError)
+	pattern := fs.String("pattern", "", "DNA pattern to search for (required)")
+	d := fs.Int("d", 0, "maximum number of mismatches")
+	out := fs.String("out", "", "output file (default standard output)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: Alignment search -pattern ACGT... [flags] file.fasta ...")
+		fs.PrintDefaults()
+	}
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *pattern == "" {
+		return errors.New("-pattern is required")
+	}
+	if *d < 0 {
+		return errors.New("-d must not be negative")
+	}
+	if fs.NArg() == 0 {
+		return errors.New("search needs at least one FASTA file")
+	}
+
+	records, err := readFASTAFiles(fs.Args())
+	if err != nil {
+		return err
+	}
+
+	forward := strings.ToUpper(*pattern)
+	reverse := Functions.ReverseComplement(forward)
+	var b strings.Builder
+	b.WriteString("#record\tstrand\tposition\tmismatches\tsite\n")
+	for _, record := range records {
+		genome := strings.ToUpper(record.Sequence)
+		for _, strand := range []struct {
+			sign    string
+			pattern string
+		}{{"+", forward}, {"-", reverse}} {
+			for _, pos := range Functions.ApproximatePatternMatching(strand.pattern, genome, *d) {
+				site := genome[pos : pos+len(strand.pattern)]
+				fmt.Fprintf(&b, "%s\t%s\t%d\t%d\t%s\n", record.ID, strand.sign, pos+1,
+					Functions.HammingDistance(strand.pattern, site), site)
+			}
+			//a palindromic pattern would be reported twice
+			if forward == reverse {
+				break
+			}
+		}
+	}
+
+	return writeOutputString(*out, b.String())
+}
+
+//runSketch implements "sketch", which writes MinHash sketches of every record of one or more
+//FASTA files to a sketch database.
+func runSketch(args []string) error {
+	fs := flag.NewFlagSet("sketch", flag.ContinueOnError)
+	k := fs.Int("k", 21, "k-mer length")
+	size := fs.Int("size", 1000, "number of hashes kept in each sketch")
+	out := fs.String("out", "", "sketch database to write (default standard output)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: Alignment sketch [flags] file.fasta ...")
+		fs.PrintDefaults()
+	}
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *k <= 0 || *size <= 0 {
+		return errors.New("-k and -size must be positive")
+	}
+	if fs.NArg() == 0 {
+		return errors.New("sketch needs at least one FASTA file")
+	}
+
+	records, err := readFASTAFiles(fs.Args())
+	if err != nil {
+		return err
+	}
+	sketches := make([]Functions.Sketch, len(records))
+	for i, record := range records {
+		sketches[i] = Functions.SketchSequence(record.ID, record.Sequence, *k, *size)
+	}
+
+	return writeOutput(*out, func(w io.Writer) error {
+		return Functions.WriteSketches(w, sketches)
+	})
+}
+
+//runScreen implements "screen", which estimates the Jaccard similarity and Mash distance between
+//sequences and a sketch database, or between every pair of sketches in the database.
+func runScreen(args []string) error {
+	fs := flag.NewFlagSet("screen", flag.ContinueOnError)
+	db := fs.String("db", "", "sketch database written by the sketch command (required)")
+	maxDistance := fs.Float64("max-distance", 1, "report only pairs within this Mash distance")
+	out := fs.String("out", "", "output file (default standard output)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: Alignment screen -db sketches.txt [flags] [query.fasta ...]")
+		fmt.Fprintln(fs.Output(), "Without FASTA files, every pair of sketches in the database is compared.")
+		fs.PrintDefaults()
+	}
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *db == "" {
+		return errors.New("-db is required")
+	}
+
+	references, err := Functions.ReadSketchFile(*db)
+	if err != nil {
+		return err
+	}
+	if len(references) == 0 {
+		return errors.New("sketch database is empty")
+	}
+
+	var b strings.Builder
+	b.WriteString("#query\treference\tjaccard\tdistance\n")
+	report := func(query, reference Functions.Sketch) {
+		if d := Functions.MashDistance(query, reference); d <= *maxDistance {
+			fmt.Fprintf(&b, "%s\t%s\t%.4f\t%.6f\n", query.Name, reference.Name,
+				Functions.JaccardEstimate(query, reference), d)
+		}
+	}
+
+	if fs.NArg() == 0 {
+		for i := range references {
+			for j := i + 1; j < len(references); j++ {
+				if references[i].K != references[j].K {
+					return fmt.Errorf("sketches %s and %s use different k-mer lengths", references[i].Name, references[j].Name)
+				}
+				report(references[i], references[j])
+			}
+		}
+	} else {
+		records, err := readFASTAFiles(fs.Args())
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			//sketch each query once for every k-mer length and size used in the database
+			sketches := make(map[[2]int]Functions.Sketch)
+			for _, reference := range references {
+				key := [2]int{reference.K, reference.Size}
+				query, ok := sketches[key]
+				if !ok {
+					query = Functions.SketchSequence(record.ID, record.Sequence, reference.K, reference.Size)
+					sketches[key] = query
+				}
+				report(query, reference)
+			}
+		}
+	}
+
+	return writeOutputString(*out, b.String())
+}
+
+//runHSP implements "hsp", which finds high-scoring segment pairs between the records of a
+//query FASTA file and those of a subject FASTA file by seed-and-extend.
+func runHSP(args []string) error {
+	fs := flag.NewFlagSet("hsp", flag.ContinueOnError)
+	var scoring scoringFlags
+	scoring.registerWithDefaults(fs, Functions.DefaultSearchMatch, Functions.DefaultSearchMismatch, Functions.DefaultSearchGap)
+	defaults := Functions.DefaultSearchParameters()
+	var params Functions.SearchParameters
+	queryFile := fs.String("query", "", "FASTA file of query sequences (required)")
+	subjectFile := fs.String("subject", "", "FASTA file of subject sequences (required)")
+	k := fs.Int("k", 11, "seed length")
+	fs.Float64Var(&params.UngappedXDrop, "xdrop-ungapped", defaults.UngappedXDrop, "score drop ending an ungapped extension")
+	fs.Float64Var(&params.GappedXDrop, "xdrop-gapped", defaults.GappedXDrop, "score drop ending a gapped extension")
+	fs.Float64Var(&params.UngappedCutoff, "cutoff", defaults.UngappedCutoff, "ungapped score needed to try a gapped extension")
+	fs.Float64Var(&params.MinScore, "min-score", defaults.MinScore, "smallest HSP score reported")
+	out := fs.String("out", "", "output file (default standard output)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *queryFile == "" || *subjectFile == "" {
+		return errors.New("both -query and -subject are required")
+	}
+	if *k <= 0 {
+		return errors.New("-k must be positive")
+	}
+	if scoring.affine() {
+		return errors.New("hsp supports only linear gap penalties")
+	}
+
+	queries, err := ReadFASTARecords(*queryFile)
+	if err != nil {
+		return err
+	}
+	subjects, err := ReadFASTARecords(*subjectFile)
+	if err != nil {
+		return err
+	}
+	matrix, useMatrix, err := scoring.scoringMatrix()
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	b.WriteString("#query\tsubject\tscore\tquery_start\tquery_end\tsubject_start\tsubject_end\tlength\tidentity\n")
+	//seeds must match exactly, so soft-masked (lowercase) sequence is uppercased first
+	for _, subject := range subjects {
+		index := Functions.IndexKmers(strings.ToUpper(subject.Sequence), *k)
+		for _, query := range queries {
+			sequence := strings.ToUpper(query.Sequence)
+			var hsps []Functions.HSP
+			if useMatrix {
+				hsps = Functions.SeedAndExtendWithMatrix(sequence, index, matrix, scoring.gap, params)
+			} else {
+				hsps = Functions.SeedAndExtend(sequence, index, scoring.match, scoring.mismatch, scoring.gap, params)
+			}
+			for _, hsp := range hsps {
+				identical := 0
+				for i := range hsp.Alignment[0] {
+					if hsp.Alignment[0][i] == hsp.Alignment[1][i] {
+						identical++
+					}
+				}
+				length := len(hsp.Alignment[0])
+				fmt.Fprintf(&b, "%s\t%s\t%g\t%d\t%d\t%d\t%d\t%d\t%.2f\n", query.ID, subject.ID, hsp.Score,
+					hsp.QueryStart+1, hsp.QueryEnd, hsp.SubjectStart+1, hsp.SubjectEnd, length,
+					100*float64(identical)/float64(length))
+			}
+		}
+	}
+
+	return writeOutputString(*out, b.String())
+}
+
+//runLCS implements "lcs", which writes a longest common subsequence of two sequences.
+func runLCS(args []string) error {
+	fs := flag.NewFlagSet("lcs", flag.ContinueOnError)
+	var inputs pairFlags
+	inputs.register(fs)
+	lengthOnly := fs.Bool("length", false, "print only the length of the LCS")
+	out := fs.String("out", "", "output file (default standard output)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	r0, r1, err := inputs.read()
+	if err != nil {
+		return err
+	}
+
+	var result string
+	if *lengthOnly {
+		result = strconv.Itoa(Functions.LCSLength(r0.Sequence, r1.Sequence))
+	} else {
+		result = Functions.LongestCommonSubsequence(r0.Sequence, r1.Sequence)
+	}
+
+	return writeOutputString(*out, result+"\n")
+}
+
+//runKmers implements "kmers", which prints the number of k-mers shared by two sequences.
+func runKmers(args []string) error {
+	fs := flag.NewFlagSet("kmers", flag.ContinueOnError)
+	var inputs pairFlags
+	inputs.register(fs)
+	k := fs.Int("k", 10, "k-mer length")
+	canonical := fs.Bool("canonical", false, "also share k-mers with their reverse complements (both strands)")
+	report := fs.Bool("report", false, "list each shared canonical k-mer with its counts and orientation instead of the total")
+	out := fs.String("out", "", "output file (default standard output)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *k <= 0 {
+		return errors.New("-k must be positive")
+	}
+
+	r0, r1, err := inputs.read()
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	if *report {
+		b.WriteString("#kmer\tforward0\treverse0\tforward1\treverse1\tsame\topposite\torientation\n")
+		for _, s := range Functions.SharedKmerReport(r0.Sequence, r1.Sequence, *k) {
+			fmt.Fprintf(&b, "%s\t%d\t%d\t%d\t%d\t%d\t%d\t%s\n", s.Kmer, s.Forward1, s.Reverse1,
+				s.Forward2, s.Reverse2, s.Same, s.Opposite, s.Orientation)
+		}
+	} else if *canonical {
+		b.WriteString(strconv.Itoa(Functions.CountSharedCanonicalKmers(r0.Sequence, r1.Sequence, *k)) + "\n")
+	} else {
+		b.WriteString(strconv.Itoa(Functions.CountSharedKmers(r0.Sequence, r1.Sequence, *k)) + "\n")
+	}
+
+	return writeOutputString(*out, b.String())
+}
+
+//runAnnotate implements "annotate", which globally aligns an annotated genome (-in0) against
+//another genome (-in1) and reports the gene, codon, and amino acid change of each difference.
+func runAnnotate(args []string) error {
+	fs := flag.NewFlagSet("annotate", flag.ContinueOnError)
+	var scoring scoringFlags
+	var inputs pairFlags
+	scoring.register(fs)
+	inputs.register(fs)
+	annotation := fs.String("annotation", "Data/Coronaviruses/SARS-CoV-2_annotation.gff3", "GFF3 or GenBank annotation of the -in0 genome")
+	method := fs.String("method", "linear", "global alignment algorithm: full, linear (Hirschberg), or banded")
+	out := fs.String("out", "", "output file (default standard output)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	r0, r1, err := inputs.read()
+	if err != nil {
+		return err
+	}
+	matrix, useMatrix, err := scoring.scoringMatrix()
+	if err != nil {
+		return err
+	}
+	features, err := Functions.ReadAnnotationFile(*annotation)
+	if err != nil {
+		return err
+	}
+
+	a, err := globalAlign(r0.Sequence, r1.Sequence, scoring, matrix, useMatrix, *method)
+	if err != nil {
+		return err
+	}
+	variants := Functions.AnnotateVariants(a, 0, features)
+
+	return writeOutput(*out, func(w io.Writer) error {
+		return WriteAnnotationReport(w, variants)
+	})
+}
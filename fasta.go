@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+//Record is a single named sequence from a FASTA file. The header line ">ID description"
+//is split at the first space into the ID and the (possibly empty) description.
+type Record struct {
+	ID          string
+	Description string
+	Sequence    string
+}
+
+//Errors describing malformed FASTA input. They are wrapped in a *FASTAError giving the
+//location of the problem, so callers should test for them with errors.Is.
+var (
+	ErrDataBeforeHeader = errors.New("sequence data before first header")
+	ErrEmptyRecord      = errors.New("record has no sequence")
+	ErrInvalidCharacter = errors.New("invalid sequence character")
+)
+
+//FASTAError reports malformed FASTA input along with the line and record where it was found.
+type FASTAError struct {
+	Line     int
+	RecordID string
+	Err      error
+	Detail   string
+}
+
+func (e *FASTAError) Error() string {
+	msg := fmt.Sprintf("line %d", e.Line)
+	if e.RecordID != "" {
+		msg += fmt.Sprintf(" (record %s)", e.RecordID)
+	}
+	msg += ": " + e.Err.Error()
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	return msg
+}
+
+func (e *FASTAError) Unwrap() error {
+	return e.Err
+}
+
+//FASTAScanner reads the records of a FASTA file one at a time, so that large multi-record
+//files never have to be held in memory all at once. It is used like a bufio.Scanner:
+//
+//	scanner := NewFASTAScanner(file)
+//	for scanner.Scan() {
+//		record := scanner.Record()
+//		...
+//	}
+//	if scanner.Err() != nil { ... }
+type FASTAScanner struct {
+	reader     *bufio.Reader
+	record     Record
+	header     string // header of the next record, already read
+	headerLine int    // line number of that header
+	line       int
+	done       bool
+	err        error
+}
+
+//NewFASTAScanner returns a FASTAScanner reading from r.
+func NewFASTAScanner(r io.Reader) *FASTAScanner {
+	return &FASTAScanner{reader: bufio.NewReader(r)}
+}
+
+//Scan advances the scanner to the next record, which is then available through Record.
+//It returns false when there are no more records or an error occurred.
+func (s *FASTAScanner) Scan() bool {
+	if s.done {
+		return false
+	}
+
+	var sequence strings.Builder
+	haveRecord := false
+	recordLine := 0
+
+	if s.header != "" {
+		s.record = parseFASTAHeader(s.header)
+		recordLine = s.headerLine
+		s.header = ""
+		haveRecord = true
+	}
+
+	for {
+		line, err := s.reader.ReadString('\n')
+		if len(line) > 0 {
+			s.line++
+		}
+		line = strings.TrimSpace(line)
+
+		if len(line) > 0 && line[0] == '>' {
+			if haveRecord {
+				//this header belongs to the next record
+				s.header = line
+				s.headerLine = s.line
+				return s.finishRecord(sequence.String(), recordLine)
+			}
+			s.record = parseFASTAHeader(line)
+			recordLine = s.line
+			haveRecord = true
+		} else if len(line) > 0 {
+			if !haveRecord {
+				return s.fail(&FASTAError{Line: s.line, Err: ErrDataBeforeHeader})
+			}
+			if i := invalidSequenceIndex(line); i >= 0 {
+				return s.fail(&FASTAError{Line: s.line, RecordID: s.record.ID, Err: ErrInvalidCharacter,
+					Detail: fmt.Sprintf("%q at column %d", line[i], i+1)})
+			}
+			sequence.WriteString(line)
+		}
+
+		if err != nil {
+			s.done = true
+			if err != io.EOF {
+				s.err = err
+				return false
+			}
+			if !haveRecord {
+				return false
+			}
+			return s.finishRecord(sequence.String(), recordLine)
+		}
+	}
+}
+
+//finishRecord sets the sequence of the current record, failing if it is empty.
+func (s *FASTAScanner) finishRecord(sequence string, recordLine int) bool {
+	if len(sequence) == 0 {
+		return s.fail(&FASTAError{Line: recordLine, RecordID: s.record.ID, Err: ErrEmptyRecord})
+	}
+	s.record.Sequence = sequence
+	return true
+}
+
+//fail records an error and stops the scanner.
+func (s *FASTAScanner) fail(err error) bool {
+	s.err = err
+	s.done = true
+	return false
+}
+
+//Record returns the record read by the most recent call to Scan.
+func (s *FASTAScanner) Record() Record {
+	return s.record
+}
+
+//Err returns the first error other than io.EOF encountered while scanning.
+func (s *FASTAScanner) Err() error {
+	return s.err
+}
+
+//invalidSequenceIndex returns the index of the first symbol of a sequence line that is not
+//a letter, a stop codon "*", or a gap "-", or -1 if every symbol is valid.
+func invalidSequenceIndex(line string) int {
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || c == '*' || c == '-' {
+			continue
+		}
+		return i
+	}
+	return -1
+}
+
+//parseFASTAHeader takes a header line starting with ">" and returns a record
+//with its ID and description set.
+func parseFASTAHeader(line string) Record {
+	header := strings.TrimSpace(line[1:])
+	var r Record
+	if i := strings.IndexAny(header, " \t"); i >= 0 {
+		r.ID = header[:i]
+		r.Description = strings.TrimSpace(header[i+1:])
+	} else {
+		r.ID = header
+	}
+	return r
+}
+
+//ReadFASTARecords takes a file name and returns every record in the FASTA file, in order.
+func ReadFASTARecords(filename string) ([]Record, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	records := make([]Record, 0)
+	scanner := NewFASTAScanner(file)
+	for scanner.Scan() {
+		records = append(records, scanner.Record())
+	}
+	if scanner.Err() != nil {
+		return nil, fmt.Errorf("%s: %w", filename, scanner.Err())
+	}
+	return records, nil
+}
+
+//WriteFASTA takes a writer, a collection of records, and a line width. It writes the records
+//in FASTA format, wrapping sequences after lineWidth symbols (or never, if lineWidth <= 0).
+func WriteFASTA(w io.Writer, records []Record, lineWidth int) error {
+	writer := bufio.NewWriter(w)
+	for _, r := range records {
+		header := ">" + r.ID
+		if r.Description != "" {
+			header += " " + r.Description
+		}
+		if _, err := fmt.Fprintln(writer, header); err != nil {
+			return err
+		}
+
+		seq := r.Sequence
+		for lineWidth > 0 && len(seq) > lineWidth {
+			if _, err := fmt.Fprintln(writer, seq[:lineWidth]); err != nil {
+				return err
+			}
+			seq = seq[lineWidth:]
+		}
+		if _, err := fmt.Fprintln(writer, seq); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
+
+//WriteFASTAFile takes a file name, a collection of records, and a line width, and writes
+//the records to the file as a FASTA.
+func WriteFASTAFile(filename string, records []Record, lineWidth int) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	if err := WriteFASTA(file, records, lineWidth); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
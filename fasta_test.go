@@ -0,0 +1,181 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+/********************************************
+ FASTA Scanner Tests
+*********************************************/
+
+type fastaTestpair struct {
+	input   string
+	records []Record
+}
+
+var fastaTests = []fastaTestpair{
+	{"", []Record{}},
+	{">seq1\nACGT\n", []Record{{ID: "seq1", Sequence: "ACGT"}}},
+	// several records, with descriptions split from the ID at the first space or tab
+	{">seq1 first record\nACGT\n>seq2\tsecond  record\nGGCC\n>seq3\nTTAA\n", []Record{
+		{ID: "seq1", Description: "first record", Sequence: "ACGT"},
+		{ID: "seq2", Description: "second  record", Sequence: "GGCC"},
+		{ID: "seq3", Sequence: "TTAA"}}},
+	// sequences wrapped over several lines are joined
+	{">wrapped\nACGTA\nCGTAC\nGT\n>short\nA\nC\n", []Record{
+		{ID: "wrapped", Sequence: "ACGTACGTACGT"},
+		{ID: "short", Sequence: "AC"}}},
+	// Windows line endings, blank lines, surrounding spaces, and no final newline
+	{"\r\n>seq1\r\nAC\r\n\r\n  GT  \r\n\n>seq2\r\nMKV*", []Record{
+		{ID: "seq1", Sequence: "ACGT"},
+		{ID: "seq2", Sequence: "MKV*"}}},
+	// case and gaps are kept
+	{">aligned\nac-GT\n", []Record{{ID: "aligned", Sequence: "ac-GT"}}}}
+
+func TestFASTAScanner(t *testing.T) {
+	for _, pair := range fastaTests {
+		records := make([]Record, 0)
+		scanner := NewFASTAScanner(strings.NewReader(pair.input))
+		for scanner.Scan() {
+			records = append(records, scanner.Record())
+		}
+		if scanner.Err() != nil || !reflect.DeepEqual(records, pair.records) {
+			t.Error(
+				"For", strings.Replace(pair.input, "\n", "\\n", -1),
+				"expected", pair.records,
+				"got", records, scanner.Err(),
+			)
+		}
+		if scanner.Scan() {
+			t.Error("expected Scan to keep returning false after the last record")
+		}
+	}
+}
+
+func TestReadFASTARecords(t *testing.T) {
+	records := []Record{
+		{ID: "first", Description: "a long record", Sequence: strings.Repeat("ACGT", 30)},
+		{ID: "second", Sequence: "GATTACA"}}
+
+	// a file written with wrapped lines reads back as the same records
+	filename := filepath.Join(t.TempDir(), "records.fasta")
+	if err := WriteFASTAFile(filename, records, 50); err != nil {
+		t.Fatal(err)
+	}
+	v, err := ReadFASTARecords(filename)
+	if err != nil || !reflect.DeepEqual(v, records) {
+		t.Error("expected", records, "got", v, err)
+	}
+
+	// the coronavirus genomes are single records wrapped at 70 symbols
+	genome, err := ReadFASTARecord("Data/Coronaviruses/SARS-CoV-2_genome.fasta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if genome.ID != "NC_045512.2" || len(genome.Sequence) != 29903 {
+		t.Error("expected NC_045512.2 with 29903 bases, got", genome.ID, len(genome.Sequence))
+	}
+}
+
+/********************************************
+ FASTA Error Tests
+*********************************************/
+
+type fastaErrorTestpair struct {
+	input    string
+	err      error
+	line     int
+	recordID string
+	message  string
+	records  int // records read before the error
+}
+
+var fastaErrorTests = []fastaErrorTestpair{
+	{"ACGT\n>seq1\nACGT\n", ErrDataBeforeHeader, 1, "",
+		"line 1: sequence data before first header", 0},
+	{"\n\nACGT\n", ErrDataBeforeHeader, 3, "",
+		"line 3: sequence data before first header", 0},
+	{">seq1\n>seq2\nACGT\n", ErrEmptyRecord, 1, "seq1",
+		"line 1 (record seq1): record has no sequence", 0},
+	{">seq1\nACGT\n>seq2\n\n", ErrEmptyRecord, 3, "seq2",
+		"line 3 (record seq2): record has no sequence", 1},
+	{">seq1\nAC1T\n", ErrInvalidCharacter, 2, "seq1",
+		"line 2 (record seq1): invalid sequence character: '1' at column 3", 0},
+	{">seq1\nACGT\n>seq2 with description\nAC\nGT.A\n", ErrInvalidCharacter, 5, "seq2",
+		"line 5 (record seq2): invalid sequence character: '.' at column 3", 1}}
+
+func TestFASTAErrors(t *testing.T) {
+	for _, pair := range fastaErrorTests {
+		records := 0
+		scanner := NewFASTAScanner(strings.NewReader(pair.input))
+		for scanner.Scan() {
+			records++
+		}
+
+		var fastaErr *FASTAError
+		err := scanner.Err()
+		if !errors.Is(err, pair.err) || !errors.As(err, &fastaErr) {
+			t.Error("For", strings.Replace(pair.input, "\n", "\\n", -1), "expected", pair.err, "got", err)
+			continue
+		}
+		if fastaErr.Line != pair.line || fastaErr.RecordID != pair.recordID || err.Error() != pair.message {
+			t.Error(
+				"For", strings.Replace(pair.input, "\n", "\\n", -1),
+				"expected", pair.message, "at line", pair.line, "of record", pair.recordID,
+				"got", err.Error(), "at line", fastaErr.Line, "of record", fastaErr.RecordID,
+			)
+		}
+		if records != pair.records {
+			t.Error("expected", pair.records, "records before the error, got", records)
+		}
+		if scanner.Scan() {
+			t.Error("expected Scan to keep returning false after an error")
+		}
+	}
+}
+
+func TestFASTAReadError(t *testing.T) {
+	// errors from the reader are passed on unchanged
+	failure := errors.New("disk failure")
+	scanner := NewFASTAScanner(io.MultiReader(strings.NewReader(">seq1\nAC"), iotest.ErrReader(failure)))
+	if scanner.Scan() || scanner.Err() != failure {
+		t.Error("expected the reader's error, got", scanner.Err())
+	}
+}
+
+func TestReadFASTARecordsErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := ReadFASTARecords(filepath.Join(dir, "missing.fasta")); !errors.Is(err, os.ErrNotExist) {
+		t.Error("expected a missing file error, got", err)
+	}
+
+	// malformed files give an error naming the file that can still be matched
+	filename := filepath.Join(dir, "bad.fasta")
+	if err := os.WriteFile(filename, []byte(">seq1\nAC GT\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := ReadFASTARecords(filename)
+	if !errors.Is(err, ErrInvalidCharacter) || !strings.HasPrefix(err.Error(), filename+": line 2") {
+		t.Error("expected an invalid character error naming", filename, "got", err)
+	}
+
+	// ReadFASTARecord and ReadFASTAFile need exactly one record
+	filename = filepath.Join(dir, "two.fasta")
+	if err := os.WriteFile(filename, []byte(">seq1\nACGT\n>seq2\nACGT\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadFASTARecord(filename); err == nil || !strings.Contains(err.Error(), "found 2") {
+		t.Error("expected an error for two records, got", err)
+	}
+	if _, err := ReadFASTAFile(filename); err == nil {
+		t.Error("expected an error for two records")
+	}
+}
@@ -2,9 +2,7 @@ package main
 
 import (
 	"Alignment/Functions"
-	"bufio"
 	"fmt"
-	"os"
 )
 
 func PrintAlignment(a Functions.Alignment) {
@@ -12,49 +10,33 @@ func PrintAlignment(a Functions.Alignment) {
 	fmt.Println(a[1])
 }
 
-//ReadFASTAFile takes a file name with a single FASTA header and reads out all elements
-//as a string other than header elements.
-func ReadFASTAFile(filename string) string {
-	file, err := os.Open(filename)
+//ReadFASTAFile takes a file name with a single FASTA header and returns its sequence
+//as a string. It returns an error if the file can't be read, is malformed, or holds
+//more than one record; use ReadFASTARecords to read multi-record files.
+func ReadFASTAFile(filename string) (string, error) {
+	record, err := ReadFASTARecord(filename)
+	return record.Sequence, err
+}
 
+//ReadFASTARecord takes a file name with a single FASTA header and returns its record.
+//It returns an error if the file doesn't hold exactly one record.
+func ReadFASTARecord(filename string) (Record, error) {
+	records, err := ReadFASTARecords(filename)
 	if err != nil {
-		// error in opening file
-		panic("Error: something went wrong with file open (probably you gave wrong filename).")
+		return Record{}, err
 	}
-
-	scanner := bufio.NewScanner(file)
-	genome := ""
-	for scanner.Scan() {
-		currentLine := scanner.Text() // grabs one line of text and returns a string
-		if len(currentLine) > 0 && currentLine[0] != '>' {
-			genome += currentLine
-		}
+	if len(records) != 1 {
+		return Record{}, fmt.Errorf("%s: expected one FASTA record, found %d", filename, len(records))
 	}
-	return genome
+	return records[0], nil
 }
 
 //WriteAlignmentToFASTA takes an alignment and a file name and writes the alignment
 //to the file as a FASTA. It uses "string_1" and "string_2" as the headers.
-func WriteAlignmentToFASTA(a Functions.Alignment, filename string) {
-	file, err := os.Create(filename)
-	if err != nil { // panic if anything went wrong
-		panic(err)
+func WriteAlignmentToFASTA(a Functions.Alignment, filename string) error {
+	records := []Record{
+		{ID: "string_1", Sequence: a[0]},
+		{ID: "string_2", Sequence: a[1]},
 	}
-
-	writer := bufio.NewWriter(file)
-	// first header
-	fmt.Fprintln(writer, ">string_1")
-
-	//first string
-	fmt.Fprintln(writer, a[0])
-
-	//second header
-	fmt.Fprintln(writer, ">string_2")
-
-	//second string
-	fmt.Fprintln(writer, a[1])
-
-	writer.Flush()
-
-	file.Close()
+	return WriteFASTAFile(filename, records, 0)
 }
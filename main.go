@@ -1,68 +1,90 @@
 package main
 
 import (
-	"Alignment/Functions"
+	"flag"
 	"fmt"
+	"os"
 )
 
-func main() {
-	fmt.Println("Sequence alignment!")
-
-	//read in data
-
-	/*
-		//hemoglobin
-
-
-		zebrafish := ReadFASTAFile("Data/Hemoglobin/Danio_rerio_hemoglobin.fasta")
-		cow := ReadFASTAFile("Data/Hemoglobin/Bos_taurus_hemoglobin.fasta")
-		gorilla := ReadFASTAFile("Data/Hemoglobin/Gorilla_gorilla_hemoglobin.fasta")
-		human := ReadFASTAFile("Data/Hemoglobin/Homo_sapiens_hemoglobin.fasta")
-
-		//now perform alignments
-
-		match := 1.0
-		mismatch := 1.0
-		gap := 3.0
-
-
-		alignment_0 := Functions.GlobalAlignment(zebrafish, human, match, mismatch, gap)
-
-		alignment_1 := Functions.GlobalAlignment(cow, human, match, mismatch, gap)
-
-		alignment_2 := Functions.GlobalAlignment(gorilla, human, match, mismatch, gap)
-
-		fmt.Println("Human and zebrafish")
-		PrintAlignment(alignment_0)
-		fmt.Println()
+//usage describes the subcommands of the Alignment binary.
+const usage = `Usage: Alignment <command> [flags]
+
+Commands:
+  align <mode>   align two sequences; mode is global, local, semi-global, fitting, or overlap
+  distance       edit distance matrix of every record in one or more FASTA files
+  msa            progressive multiple alignment of every record in one or more FASTA files
+  tree           UPGMA or neighbor-joining tree (Newick) from FASTA files or a distance matrix
+  edits          shortest list of edits turning one sequence into another, as a diff or a list
+  search         sites of a pattern with at most d mismatches on both strands of FASTA records
+  sketch         MinHash sketches of every record in one or more FASTA files
+  screen         Jaccard and Mash distances between sequences and a sketch database
+  hsp            high-scoring segment pairs between query and subject sequences by seed-and-extend
+  lcs            longest common subsequence of two sequences
+  kmers          number of k-mers shared by two sequences
+  annotate       report the gene and amino acid change of each difference between two genomes
+
+Run "Alignment <command> -h" to see the flags of a command.
+
+Examples:
+  Alignment align global -in0 Data/Coronaviruses/SARS-CoV_genome.fasta \
+      -in1 Data/Coronaviruses/SARS-CoV-2_genome.fasta -match 1 -mismatch 10 -gap 1 \
+      -method linear -out Output/coronavirus_alignment.fasta
+  Alignment align global -in0 Data/Hemoglobin/Danio_rerio_hemoglobin.fasta \
+      -in1 Data/Hemoglobin/Homo_sapiens_hemoglobin.fasta -matrix BLOSUM62 -gap 5 -format text
+  Alignment msa -matrix BLOSUM62 -gap 5 -format text Data/Hemoglobin/*.fasta
+  Alignment tree -method upgma Data/Hemoglobin/*.fasta
+`
 
-		fmt.Println("Human and cow")
-		PrintAlignment(alignment_1)
-		fmt.Println()
-
-		fmt.Println("Human and gorilla")
-		PrintAlignment(alignment_2)
-	*/
-
-	sars := ReadFASTAFile("Data/Coronaviruses/SARS-CoV_genome.fasta")
-
-	sars2 := ReadFASTAFile("Data/Coronaviruses/SARS-CoV-2_genome.fasta")
-
-	//let's global align them
-
-	match := 1.0
-	mismatch := 10.0
-	gap := 1.0
-
-	fmt.Println("Aligning coronavirus genomes.")
-
-	SARS_alignment := Functions.GlobalAlignment(sars, sars2, match, mismatch, gap)
-
-	fmt.Println("Alignment complete! Writing to file.")
-
-	outfile := "Output/coronavirus_alignment.fasta"
-	WriteAlignmentToFASTA(SARS_alignment, outfile)
-
-	fmt.Println("Alignment written to file. Program exiting.")
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "align":
+		err = runAlign(os.Args[2:])
+	case "distance":
+		err = runDistance(os.Args[2:])
+	case "msa":
+		err = runMSA(os.Args[2:])
+	case "tree":
+		err = runTree(os.Args[2:])
+	case "edits":
+		err = runEdits(os.Args[2:])
+	case "search":
+		err = runSearch(os.Args[2:])
+	case "sketch":
+		err = runSketch(os.Args[2:])
+	case "screen":
+		err = runScreen(os.Args[2:])
+	case "hsp":
+		err = runHSP(os.Args[2:])
+	case "lcs":
+		err = runLCS(os.Args[2:])
+	case "kmers":
+		err = runKmers(os.Args[2:])
+	case "annotate":
+		err = runAnnotate(os.Args[2:])
+	case "-h", "-help", "--help", "help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %q.\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err == flag.ErrHelp {
+		return
+	}
+	if err != nil {
+		exitWithError(err)
+	}
+}
 
+//exitWithError prints an error to standard error and exits the program.
+func exitWithError(err error) {
+	fmt.Fprintln(os.Stderr, "Error:", err)
+	os.Exit(1)
 }
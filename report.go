@@ -0,0 +1,38 @@
+package main
+
+import (
+	"Alignment/Functions"
+	"bufio"
+	"fmt"
+	"io"
+)
+
+//WriteAnnotationReport takes a writer and a collection of annotated variants, and writes them
+//as a tab-separated table with one line per variant and CDS.
+func WriteAnnotationReport(w io.Writer, variants []Functions.AnnotatedVariant) error {
+	writer := bufio.NewWriter(w)
+	fmt.Fprintln(writer, "POS\tREF\tALT\tTYPE\tGENE\tCDS\tCODON\tCODON_POS\tREF_CODON\tALT_CODON\tAA_CHANGE\tEFFECT")
+	for _, v := range variants {
+		fmt.Fprintf(writer, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			v.Pos, v.Ref, v.Alt, v.Type,
+			orDot(v.Gene), orDot(v.CDS), orDotInt(v.CodonNumber), orDotInt(v.CodonPosition),
+			orDot(v.RefCodon), orDot(v.AltCodon), orDot(v.AminoAcidChange), v.Effect)
+	}
+	return writer.Flush()
+}
+
+//orDot returns s, or "." if s is empty, as is customary for missing values in tables.
+func orDot(s string) string {
+	if s == "" {
+		return "."
+	}
+	return s
+}
+
+//orDotInt returns n as a string, or "." if n is zero.
+func orDotInt(n int) string {
+	if n == 0 {
+		return "."
+	}
+	return fmt.Sprint(n)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"Alignment/Functions"
+	"bufio"
+	"fmt"
+	"io"
+)
+
+//WriteSAM takes a writer, a reference record, a query record, and an alignment of
+//reference[start0:] (top row) against query[start1:] (bottom row), as produced by the global,
+//local, and mode alignment functions. It writes a SAM file with a single alignment line.
+//Unaligned ends of the query are soft clipped, and gaps in the query at either end of the
+//alignment are dropped by moving the alignment's reference position.
+func WriteSAM(w io.Writer, reference, query Record, a Functions.Alignment, start0, start1 int) error {
+	ops := Functions.AlignmentCIGAR(a)
+
+	//a CIGAR must start and end with an aligned symbol, so indels at either end are dropped:
+	//reference symbols skipped at the start just move the alignment's position, and query
+	//symbols inserted at either end are clipped, along with those outside the alignment
+	pos := start0 + 1
+	leadingClip := start1
+	for len(ops) > 0 && (ops[0].Op == 'D' || ops[0].Op == 'I') {
+		if ops[0].Op == 'D' {
+			pos += ops[0].Length
+		} else {
+			leadingClip += ops[0].Length
+		}
+		ops = ops[1:]
+	}
+	trailingClip := len(query.Sequence) - start1 - queryLength(a)
+	for len(ops) > 0 && (ops[len(ops)-1].Op == 'D' || ops[len(ops)-1].Op == 'I') {
+		if ops[len(ops)-1].Op == 'I' {
+			trailingClip += ops[len(ops)-1].Length
+		}
+		ops = ops[:len(ops)-1]
+	}
+	aligned := len(ops) > 0
+	if leadingClip > 0 {
+		ops = append([]Functions.CIGAROperation{{Op: 'S', Length: leadingClip}}, ops...)
+	}
+	if trailingClip > 0 {
+		ops = append(ops, Functions.CIGAROperation{Op: 'S', Length: trailingClip})
+	}
+
+	//a query that doesn't align to the reference at all is reported as unmapped
+	flag := 0
+	rname := reference.ID
+	if !aligned {
+		flag = 4
+		rname = "*"
+		pos = 0
+		ops = nil
+	}
+
+	writer := bufio.NewWriter(w)
+	fmt.Fprintln(writer, "@HD\tVN:1.6\tSO:unsorted")
+	fmt.Fprintf(writer, "@SQ\tSN:%s\tLN:%d\n", reference.ID, len(reference.Sequence))
+	fmt.Fprintln(writer, "@PG\tID:Alignment\tPN:Alignment")
+	fmt.Fprintf(writer, "%s\t%d\t%s\t%d\t255\t%s\t*\t0\t0\t%s\t*\n",
+		query.ID, flag, rname, pos, Functions.CIGARString(ops), query.Sequence)
+	return writer.Flush()
+}
+
+//queryLength returns the number of non-gap symbols in the bottom row of an alignment.
+func queryLength(a Functions.Alignment) int {
+	n := 0
+	for i := 0; i < len(a[1]); i++ {
+		if a[1][i] != '-' {
+			n++
+		}
+	}
+	return n
+}
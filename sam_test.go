@@ -0,0 +1,67 @@
+package main
+
+import (
+	"Alignment/Functions"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+/********************************************
+ SAM Tests
+*********************************************/
+
+type samTestpair struct {
+	reference Record
+	query     Record
+	alignment Functions.Alignment
+	start0    int
+	start1    int
+	line      string
+}
+
+var samTests = []samTestpair{
+	// reference[4:12] aligned to query[2:9], with a deletion and an insertion; the query
+	// symbols outside the alignment are soft clipped
+	{Record{ID: "ref", Sequence: "CCCCACGTACGTAAAA"}, Record{ID: "read1", Sequence: "TTACACTGTGGG"},
+		Functions.Alignment{"ACGTAC-GT", "AC--ACTGT"}, 4, 2,
+		"read1\t0\tref\t5\t255\t2S2=2D2=1I2=3S\t*\t0\t0\tTTACACTGTGGG\t*"},
+	// gaps in the query at the start move the position, and an insertion at the end is clipped
+	{Record{ID: "ref", Sequence: "GGACTT"}, Record{ID: "read2", Sequence: "ACGTA"},
+		Functions.Alignment{"GGACTT-", "--ACGTA"}, 0, 0,
+		"read2\t0\tref\t3\t255\t2=1X1=1S\t*\t0\t0\tACGTA\t*"},
+	// an insertion next to a deletion at either end is clipped, and the deletions dropped
+	{Record{ID: "ref", Sequence: "GGACGTCC"}, Record{ID: "read3", Sequence: "TACGTA"},
+		Functions.Alignment{"-GACGTC-", "T-ACGT-A"}, 1, 0,
+		"read3\t0\tref\t3\t255\t1S4=1S\t*\t0\t0\tTACGTA\t*"},
+	// a query with no aligned symbols is unmapped
+	{Record{ID: "ref", Sequence: "ACG"}, Record{ID: "read4", Sequence: "TTT"},
+		Functions.Alignment{"ACG", "---"}, 0, 0,
+		"read4\t4\t*\t0\t255\t*\t*\t0\t0\tTTT\t*"}}
+
+func TestWriteSAM(t *testing.T) {
+	for _, pair := range samTests {
+		var b strings.Builder
+		if err := WriteSAM(&b, pair.reference, pair.query, pair.alignment, pair.start0, pair.start1); err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+		header := []string{
+			"@HD\tVN:1.6\tSO:unsorted",
+			"@SQ\tSN:" + pair.reference.ID + "\tLN:" + strconv.Itoa(len(pair.reference.Sequence)),
+			"@PG\tID:Alignment\tPN:Alignment"}
+		if len(lines) != 4 || !reflect.DeepEqual(lines[:3], header) || lines[3] != pair.line {
+			t.Error(
+				"For", pair.alignment,
+				"expected", header, pair.line,
+				"got", lines,
+			)
+		}
+
+		// the record has the 11 mandatory SAM fields
+		if fields := strings.Split(lines[len(lines)-1], "\t"); len(fields) != 11 {
+			t.Error("expected 11 fields, got", len(fields))
+		}
+	}
+}
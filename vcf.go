@@ -0,0 +1,27 @@
+package main
+
+import (
+	"Alignment/Functions"
+	"bufio"
+	"fmt"
+	"io"
+)
+
+//WriteVCF takes a writer, the reference record, the variants called from an alignment of
+//reference[start0:] against a query, and the query's name. It writes the variants as VCF 4.2,
+//shifting their positions by start0 so that they are relative to the whole reference.
+func WriteVCF(w io.Writer, reference Record, queryName string, variants []Functions.Variant, start0 int) error {
+	writer := bufio.NewWriter(w)
+	fmt.Fprintln(writer, "##fileformat=VCFv4.2")
+	fmt.Fprintln(writer, "##source=Alignment")
+	fmt.Fprintf(writer, "##contig=<ID=%s,length=%d>\n", reference.ID, len(reference.Sequence))
+	fmt.Fprintln(writer, `##INFO=<ID=TYPE,Number=1,Type=String,Description="Variant type: SNP, INS, DEL, or COMPLEX">`)
+	fmt.Fprintln(writer, `##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">`)
+	fmt.Fprintf(writer, "#CHROM\tPOS\tID\tREF\tALT\tQUAL\tFILTER\tINFO\tFORMAT\t%s\n", queryName)
+
+	for _, v := range variants {
+		fmt.Fprintf(writer, "%s\t%d\t.\t%s\t%s\t.\tPASS\tTYPE=%s\tGT\t1\n",
+			reference.ID, v.Pos+start0, v.Ref, v.Alt, v.Type)
+	}
+	return writer.Flush()
+}